@@ -0,0 +1,83 @@
+package sn
+
+import "context"
+
+type ActType string
+
+const (
+	ActTip          ActType = "TIP"
+	ActDontLikeThis ActType = "DONT_LIKE_THIS"
+	ActBoost        ActType = "BOOST"
+)
+
+type ItemAct struct {
+	Id   int     `json:"id,string"`
+	Sats int     `json:"sats"`
+	Path string  `json:"path"`
+	Act  ActType `json:"act"`
+}
+
+type ItemActPaidAction struct {
+	Result        ItemAct       `json:"result"`
+	Invoice       Invoice       `json:"invoice"`
+	PaymentMethod PaymentMethod `json:"paymentMethod"`
+}
+
+func (c *Client) Zap(itemId int, sats int) (*ItemActPaidAction, error) {
+	return c.ZapContext(context.Background(), itemId, sats)
+}
+
+func (c *Client) ZapContext(ctx context.Context, itemId int, sats int) (*ItemActPaidAction, error) {
+	return c.ActContext(ctx, itemId, sats, ActTip)
+}
+
+func (c *Client) Downzap(itemId int, sats int) (*ItemActPaidAction, error) {
+	return c.DownzapContext(context.Background(), itemId, sats)
+}
+
+func (c *Client) DownzapContext(ctx context.Context, itemId int, sats int) (*ItemActPaidAction, error) {
+	return c.ActContext(ctx, itemId, sats, ActDontLikeThis)
+}
+
+func (c *Client) Boost(itemId int, sats int) (*ItemActPaidAction, error) {
+	return c.BoostContext(context.Background(), itemId, sats)
+}
+
+func (c *Client) BoostContext(ctx context.Context, itemId int, sats int) (*ItemActPaidAction, error) {
+	return c.ActContext(ctx, itemId, sats, ActBoost)
+}
+
+func (c *Client) Act(itemId int, sats int, act ActType) (*ItemActPaidAction, error) {
+	return c.ActContext(context.Background(), itemId, sats, act)
+}
+
+func (c *Client) ActContext(ctx context.Context, itemId int, sats int, act ActType) (*ItemActPaidAction, error) {
+	query := invoiceFields + `
+	mutation act($id: ID!, $sats: Int, $act: String) {
+		act(id: $id, sats: $sats, act: $act) {
+			result {
+				id
+				sats
+				path
+				act
+			}
+			invoice {
+				...InvoiceFields
+			}
+			paymentMethod
+		}
+	}`
+	variables := map[string]interface{}{
+		"id":   itemId,
+		"sats": sats,
+		"act":  act,
+	}
+
+	data, err := Do[struct {
+		Act ItemActPaidAction `json:"act"`
+	}](ctx, c, query, variables)
+	if err != nil {
+		return nil, err
+	}
+	return &data.Act, nil
+}
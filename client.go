@@ -2,18 +2,25 @@ package sn
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"regexp"
+	"time"
 )
 
 type Client struct {
-	BaseUrl  string
-	ApiUrl   string
-	ApiKey   string
-	MediaUrl string
+	BaseUrl     string
+	ApiUrl      string
+	ApiKey      string
+	MediaUrl    string
+	HttpClient  *http.Client
+	RetryPolicy *RetryPolicy
+	Limiter     Limiter
 }
 
 func NewClient(options ...func(*Client)) *Client {
@@ -32,6 +39,9 @@ func NewClient(options ...func(*Client)) *Client {
 	if c.MediaUrl == "" {
 		c.MediaUrl = "https://m.stacker.news"
 	}
+	if c.HttpClient == nil {
+		c.HttpClient = http.DefaultClient
+	}
 	c.ApiUrl = fmt.Sprintf("%s/api/graphql", c.BaseUrl)
 
 	return c
@@ -55,48 +65,217 @@ func WithMediaUrl(mediaUrl string) func(*Client) {
 	}
 }
 
+func WithHttpClient(httpClient *http.Client) func(*Client) {
+	return func(c *Client) {
+		c.HttpClient = httpClient
+	}
+}
+
+// WithTimeout, WithTransport and WithProxy modify a copy of the current HTTP client
+// so http.DefaultClient or a client passed via WithHttpClient is never mutated.
+// Options are applied in order: pass WithHttpClient before them since
+// a later WithHttpClient replaces the client they modified.
+func WithTimeout(timeout time.Duration) func(*Client) {
+	return func(c *Client) {
+		c.HttpClient = c.ownHttpClient()
+		c.HttpClient.Timeout = timeout
+	}
+}
+
+func WithTransport(transport http.RoundTripper) func(*Client) {
+	return func(c *Client) {
+		c.HttpClient = c.ownHttpClient()
+		c.HttpClient.Transport = transport
+	}
+}
+
+// WithProxy routes all requests through the given proxy.
+// SOCKS5 proxies like Tor are supported via the socks5:// scheme.
+func WithProxy(proxyUrl *url.URL) func(*Client) {
+	return func(c *Client) {
+		c.HttpClient = c.ownHttpClient()
+		transport, ok := c.HttpClient.Transport.(*http.Transport)
+		if ok {
+			transport = transport.Clone()
+		} else {
+			transport = http.DefaultTransport.(*http.Transport).Clone()
+		}
+		transport.Proxy = http.ProxyURL(proxyUrl)
+		c.HttpClient.Transport = transport
+	}
+}
+
+// httpClient returns the HTTP client to send requests with.
+// Clients that were not created with NewClient may have no HTTP client set.
+func (c *Client) httpClient() *http.Client {
+	if c.HttpClient == nil {
+		return http.DefaultClient
+	}
+	return c.HttpClient
+}
+
+func (c *Client) ownHttpClient() *http.Client {
+	if c.HttpClient == nil {
+		return &http.Client{}
+	}
+	httpClient := *c.HttpClient
+	return &httpClient
+}
+
 type GqlBody struct {
 	Query     string                 `json:"query"`
 	Variables map[string]interface{} `json:"variables,omitempty"`
 }
 
 type GqlError struct {
-	Message string `json:"message"`
+	Message    string             `json:"message"`
+	Path       []interface{}      `json:"path,omitempty"`
+	Extensions GqlErrorExtensions `json:"extensions"`
 }
 
-func (c *Client) callApi(body GqlBody) (*http.Response, error) {
-	bodyJSON, err := json.Marshal(body)
+type GqlErrorExtensions struct {
+	Code string `json:"code"`
+}
+
+// GqlResponse is the body of a GraphQL response.
+// The *Response types of this package are aliases of it with the data of a single operation.
+type GqlResponse[T any] struct {
+	Errors []GqlError `json:"errors"`
+	Data   T          `json:"data"`
+}
+
+// Do runs the GraphQL operation and decodes the data of the response into T.
+// It can be used to run operations that are not wrapped by this package.
+func Do[T any](ctx context.Context, c *Client, query string, variables map[string]interface{}) (T, error) {
+	var (
+		zero     T
+		respBody GqlResponse[T]
+	)
+
+	resp, err := c.callApi(ctx, GqlBody{Query: query, Variables: variables})
 	if err != nil {
-		err = fmt.Errorf("error encoding SN payload: %w", err)
-		return nil, err
+		return zero, err
 	}
+	defer resp.Body.Close()
 
-	req, err := http.NewRequest("POST", c.ApiUrl, bytes.NewBuffer(bodyJSON))
+	err = json.NewDecoder(resp.Body).Decode(&respBody)
 	if err != nil {
-		err = fmt.Errorf("error preparing SN request: %w", err)
-		return nil, err
+		err = fmt.Errorf("error decoding %s: %w", operationName(query), err)
+		return zero, err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	if c.ApiKey != "" {
-		req.Header.Set("X-Api-Key", c.ApiKey)
+
+	err = c.checkForErrors(respBody.Errors)
+	if err != nil {
+		return zero, err
 	}
+	return respBody.Data, nil
+}
 
-	client := http.DefaultClient
-	resp, err := client.Do(req)
+func (c *Client) callApi(ctx context.Context, body GqlBody) (*http.Response, error) {
+	bodyJSON, err := json.Marshal(body)
 	if err != nil {
+		err = fmt.Errorf("error encoding SN payload: %w", err)
 		return nil, err
 	}
 
-	return resp, nil
+	newRequest := func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", c.ApiUrl, bytes.NewReader(bodyJSON))
+		if err != nil {
+			err = fmt.Errorf("error preparing SN request: %w", err)
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if c.ApiKey != "" {
+			req.Header.Set("X-Api-Key", c.ApiKey)
+		}
+		return req, nil
+	}
+
+	return c.do(ctx, newRequest, !isMutation(body.Query))
+}
+
+// do sends the request returned by newRequest and retries it according to the retry policy.
+// newRequest is called for every attempt since request bodies can only be read once.
+func (c *Client) do(ctx context.Context, newRequest func() (*http.Request, error), idempotent bool) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		if c.Limiter != nil {
+			if err := c.Limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := c.httpClient().Do(req)
+		if err == nil {
+			if err = checkStatus(resp); err == nil {
+				return resp, nil
+			}
+		}
+
+		if attempt >= c.RetryPolicy.maxAttempts() || !isRetryable(err, idempotent) {
+			return nil, err
+		}
+
+		wait := c.RetryPolicy.backoff(attempt, err)
+		if c.RetryPolicy.OnRetry != nil {
+			c.RetryPolicy.OnRetry(RetryAttempt{Attempt: attempt, Err: err, Wait: wait})
+		}
+		if err = sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+var mutationRegexp = regexp.MustCompile(`(?m)^\s*mutation\b`)
+
+func isMutation(query string) bool {
+	return mutationRegexp.MatchString(query)
+}
+
+var operationNameRegexp = regexp.MustCompile(`(?:query|mutation)\s+(\w+)`)
+
+func operationName(query string) string {
+	if m := operationNameRegexp.FindStringSubmatch(query); m != nil {
+		return m[1]
+	}
+	return "SN response"
 }
 
 func (c *Client) checkForErrors(err []GqlError) error {
 	if len(err) > 0 {
-		errMsg, marshalErr := json.Marshal(err)
-		if marshalErr != nil {
-			return marshalErr
-		}
-		return errors.New(string(errMsg))
+		return newGraphQLError(err)
 	}
 	return nil
 }
+
+// checkStatus closes the response body and returns an error if the response has a non-2xx status code.
+// GraphQL errors in the response are preferred over a generic *HTTPError.
+func checkStatus(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return fmt.Errorf("error reading SN response: %w", err)
+	}
+
+	var respBody struct {
+		Errors []GqlError `json:"errors"`
+	}
+	if json.Unmarshal(body, &respBody) == nil && len(respBody.Errors) > 0 {
+		return newGraphQLError(respBody.Errors)
+	}
+
+	return &HTTPError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Header:     resp.Header,
+		Body:       string(body),
+	}
+}
@@ -6,16 +6,21 @@ import (
 	"log"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 
 	sn "github.com/ekzyis/snappy"
 )
 
 var (
-	c = testClient()
+	snClient     *sn.Client
+	snClientErr  error
+	snClientOnce sync.Once
 )
 
 func TestQueryItems(t *testing.T) {
+	c := testClient(t)
+
 	var (
 		cursor *sn.ItemsCursor
 		err    error
@@ -32,53 +37,153 @@ func TestQueryItems(t *testing.T) {
 	}
 }
 
+func TestQuerySearch(t *testing.T) {
+	c := testClient(t)
+
+	var (
+		cursor *sn.ItemsCursor
+		q      = sn.NewSearchTerms("test").String()
+		err    error
+	)
+
+	if cursor, err = c.Search(&sn.SearchQuery{Q: q}); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if len(cursor.Items) == 0 {
+		t.Error("search cursor empty")
+		return
+	}
+}
+
 func TestMutationCreateComment(t *testing.T) {
+	c := testClient(t)
+
 	var (
 		parentId = 349
 		text     = "test comment"
+		action   *sn.ItemPaidAction
 		err      error
 	)
 
-	// TODO: return result, invoice, paymentMethod from CreateComment and run assertions on that
-	if _, err = c.CreateComment(parentId, text); err != nil {
+	if action, err = c.CreateComment(parentId, text); err != nil {
 		t.Error(err)
 		return
 	}
+
+	assertPaidAction(t, action)
 }
 
 func TestMutationPostDiscussion(t *testing.T) {
+	c := testClient(t)
+
 	var (
-		title = "test discussion"
-		text  = "test discussion text"
-		sub   = "bitcoin"
-		err   error
+		title  = "test discussion"
+		text   = "test discussion text"
+		sub    = "bitcoin"
+		action *sn.ItemPaidAction
+		err    error
 	)
 
-	// TODO: return result, invoice, paymentMethod from CreateComment and run assertions on that
-	if _, err = c.PostDiscussion(title, text, sub); err != nil {
+	if action, err = c.PostDiscussion(title, text, sub); err != nil {
 		t.Error(err)
 		return
 	}
+
+	assertPaidAction(t, action)
 }
 
 func TestMutationPostLink(t *testing.T) {
+	c := testClient(t)
+
+	var (
+		url    = "https://stacker.news"
+		title  = "test discussion"
+		text   = "test discussion text"
+		sub    = "bitcoin"
+		action *sn.ItemPaidAction
+		err    error
+	)
+
+	if action, err = c.PostLink(url, title, text, sub); err != nil {
+		t.Error(err)
+		return
+	}
+
+	assertPaidAction(t, action)
+}
+
+func TestMutationZap(t *testing.T) {
+	c := testClient(t)
+
 	var (
-		url   = "https://stacker.news"
-		title = "test discussion"
-		text  = "test discussion text"
-		sub   = "bitcoin"
-		err   error
+		itemId = 349
+		sats   = 10
+		act    *sn.ItemActPaidAction
+		err    error
 	)
 
-	// TODO: return result, invoice, paymentMethod from CreateComment and run assertions on that
-	if _, err = c.PostLink(url, title, text, sub); err != nil {
+	if act, err = c.Zap(itemId, sats); err != nil {
 		t.Error(err)
 		return
 	}
+
+	switch act.PaymentMethod {
+	case sn.PaymentMethodFeeCredits, sn.PaymentMethodOptimistic:
+		if act.Result.Id != itemId {
+			t.Errorf("expected zap on item %d, got %d", itemId, act.Result.Id)
+			return
+		}
+
+		if act.Result.Act != sn.ActTip {
+			t.Errorf("expected act %s, got %s", sn.ActTip, act.Result.Act)
+			return
+		}
+	case sn.PaymentMethodPessimistic:
+		if act.Invoice.Bolt11 == "" {
+			t.Errorf("expected invoice for payment method %s", act.PaymentMethod)
+		}
+	default:
+		t.Errorf("unknown payment method: %s", act.PaymentMethod)
+	}
+}
+
+func assertPaidAction(t *testing.T, action *sn.ItemPaidAction) {
+	t.Helper()
+
+	switch action.PaymentMethod {
+	case sn.PaymentMethodFeeCredits, sn.PaymentMethodOptimistic:
+		if action.Result.Id == 0 {
+			t.Errorf("expected item for payment method %s", action.PaymentMethod)
+		}
+	case sn.PaymentMethodPessimistic:
+		if action.Invoice.Bolt11 == "" {
+			t.Errorf("expected invoice for payment method %s", action.PaymentMethod)
+		}
+	default:
+		t.Errorf("unknown payment method: %s", action.PaymentMethod)
+	}
 }
 
-func testClient() *sn.Client {
-	loadEnv()
+// testClient returns the client for tests against a running SN instance.
+// It is created on first use so tests that don't need SN can run without .env.
+func testClient(t *testing.T) *sn.Client {
+	t.Helper()
+
+	snClientOnce.Do(func() {
+		snClient, snClientErr = newTestClient()
+	})
+	if snClientErr != nil {
+		t.Fatal(snClientErr)
+	}
+	return snClient
+}
+
+func newTestClient() (*sn.Client, error) {
+	if err := loadEnv(); err != nil {
+		return nil, err
+	}
 
 	baseUrl, set := os.LookupEnv("TEST_SN_BASE_URL")
 	if !set {
@@ -88,17 +193,17 @@ func testClient() *sn.Client {
 
 	apiKey, set := os.LookupEnv("TEST_SN_API_KEY")
 	if !set {
-		log.Fatalf("TEST_SN_API_KEY is not set")
+		return nil, fmt.Errorf("TEST_SN_API_KEY is not set")
 	}
 	log.Printf("apiKey=%s\n", apiKey)
 
 	return sn.NewClient(
 		sn.WithBaseUrl(baseUrl),
 		sn.WithApiKey(apiKey),
-	)
+	), nil
 }
 
-func loadEnv() {
+func loadEnv() error {
 	var (
 		f   *os.File
 		s   *bufio.Scanner
@@ -106,7 +211,7 @@ func loadEnv() {
 	)
 
 	if f, err = os.Open(".env"); err != nil {
-		log.Fatalf("error opening .env: %v", err)
+		return fmt.Errorf("error opening .env: %w", err)
 	}
 	defer f.Close()
 
@@ -120,7 +225,7 @@ func loadEnv() {
 		if len(parts) == 2 {
 			os.Setenv(parts[0], parts[1])
 		} else {
-			log.Fatalf(".env: invalid line: %s\n", line)
+			return fmt.Errorf(".env: invalid line: %s", line)
 		}
 	}
 
@@ -129,4 +234,5 @@ func loadEnv() {
 		fmt.Println("error scanning .env:", err)
 	}
 
+	return nil
 }
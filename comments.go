@@ -0,0 +1,134 @@
+package sn
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+type CommentSort string
+
+const (
+	CommentSortHot    CommentSort = "hot"
+	CommentSortTop    CommentSort = "top"
+	CommentSortRecent CommentSort = "recent"
+)
+
+type CommentsOptions struct {
+	// Depth is the number of comment levels to fetch. Defaults to 10.
+	Depth int
+	// Sort defaults to hot.
+	Sort CommentSort
+}
+
+const commentFields = `
+fragment CommentFields on Item {
+	id
+	parentId
+	createdAt
+	deletedAt
+	text
+	sats
+	ncomments
+	user {
+		id
+		name
+	}
+}`
+
+// commentsSelection returns the selection set for depth levels of nested comments.
+func commentsSelection(depth int) string {
+	var b strings.Builder
+	for i := 0; i < depth; i++ {
+		b.WriteString("comments(sort: $sort) {\n...CommentFields\n")
+	}
+	b.WriteString(strings.Repeat("}\n", depth))
+	return b.String()
+}
+
+func (c *Client) ItemWithComments(id int, opts *CommentsOptions) (*Item, error) {
+	return c.ItemWithCommentsContext(context.Background(), id, opts)
+}
+
+// ItemWithCommentsContext fetches the item together with its comment tree.
+func (c *Client) ItemWithCommentsContext(ctx context.Context, id int, opts *CommentsOptions) (*Item, error) {
+	var o CommentsOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Depth <= 0 {
+		o.Depth = 10
+	}
+	if o.Sort == "" {
+		o.Sort = CommentSortHot
+	}
+
+	query := itemFields + commentFields + `
+	query item($id: ID!, $sort: String) {
+		item(id: $id) {
+			...ItemFields
+			` + commentsSelection(o.Depth) + `
+		}
+	}`
+	variables := map[string]interface{}{
+		"id":   id,
+		"sort": o.Sort,
+	}
+
+	data, err := Do[itemData](ctx, c, query, variables)
+	if err != nil {
+		return nil, err
+	}
+	if data.Item.Id == 0 {
+		return nil, fmt.Errorf("item %d: %w", id, ErrNotFound)
+	}
+	return &data.Item, nil
+}
+
+// FlattenComments returns all comments of the tree in depth-first order.
+func (i *Item) FlattenComments() []*Comment {
+	var comments []*Comment
+	walkComments(i.Comments, func(path []*Comment) bool {
+		comments = append(comments, path[len(path)-1])
+		return true
+	})
+	return comments
+}
+
+// FindComment returns the comment with the given id or nil if it is not part of the tree.
+func (i *Item) FindComment(id int) *Comment {
+	path := i.CommentPath(id)
+	if path == nil {
+		return nil
+	}
+	return path[len(path)-1]
+}
+
+// CommentPath returns the chain of comments from a top-level comment down to the comment with the given id.
+// It returns nil if the comment is not part of the tree.
+func (i *Item) CommentPath(id int) []*Comment {
+	var found []*Comment
+	walkComments(i.Comments, func(path []*Comment) bool {
+		if path[len(path)-1].Id == id {
+			found = append([]*Comment(nil), path...)
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// walkComments calls f with the path to every comment until f returns false.
+func walkComments(comments []Comment, f func(path []*Comment) bool) {
+	var walk func(comments []Comment, path []*Comment) bool
+	walk = func(comments []Comment, path []*Comment) bool {
+		for i := range comments {
+			path := append(path, &comments[i])
+			if !f(path) || !walk(comments[i].Comments, path) {
+				return false
+			}
+		}
+		return true
+	}
+	walk(comments, nil)
+}
@@ -0,0 +1,102 @@
+package sn
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+var (
+	ErrUnauthenticated   = errors.New("not logged in")
+	ErrForbidden         = errors.New("forbidden")
+	ErrBadInput          = errors.New("bad input")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrNotFound          = errors.New("not found")
+	ErrRateLimited       = errors.New("rate limited")
+	ErrEditWindowClosed  = errors.New("item can no longer be edited")
+)
+
+// GraphQLError is returned if the API responded with GraphQL errors.
+// Message, Path and Code are taken from the first error.
+type GraphQLError struct {
+	Message string
+	Path    []interface{}
+	Code    string
+	Errors  []GqlError
+}
+
+func newGraphQLError(errs []GqlError) *GraphQLError {
+	return &GraphQLError{
+		Message: errs[0].Message,
+		Path:    errs[0].Path,
+		Code:    errs[0].Extensions.Code,
+		Errors:  errs,
+	}
+}
+
+func (e *GraphQLError) Error() string {
+	var msgs []string
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Message)
+	}
+	return fmt.Sprintf("SN error: %s", strings.Join(msgs, "; "))
+}
+
+func (e *GraphQLError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if err.is(target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e GqlError) is(target error) bool {
+	msg := strings.ToLower(e.Message)
+	switch target {
+	case ErrUnauthenticated:
+		return e.Extensions.Code == "UNAUTHENTICATED" || strings.Contains(msg, "logged in")
+	case ErrForbidden:
+		return e.Extensions.Code == "FORBIDDEN"
+	case ErrBadInput:
+		return e.Extensions.Code == "BAD_INPUT" || e.Extensions.Code == "BAD_USER_INPUT"
+	case ErrInsufficientFunds:
+		return strings.Contains(msg, "insufficient funds")
+	case ErrNotFound:
+		return e.Extensions.Code == "NOT_FOUND" || strings.Contains(msg, "not found")
+	case ErrRateLimited:
+		return e.Extensions.Code == "RATE_LIMITED" || strings.Contains(msg, "too many requests")
+	case ErrEditWindowClosed:
+		return strings.Contains(msg, "can no longer be edited")
+	}
+	return false
+}
+
+// HTTPError is returned if the server responded with a non-2xx status code
+// and the response did not contain GraphQL errors, for example HTML error pages
+// from a proxy or load balancer.
+type HTTPError struct {
+	StatusCode int
+	Status     string
+	Header     http.Header
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("SN responded with %s", e.Status)
+}
+
+func (e *HTTPError) Is(target error) bool {
+	switch target {
+	case ErrUnauthenticated:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
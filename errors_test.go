@@ -0,0 +1,29 @@
+package sn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGraphQLErrorIs(t *testing.T) {
+	tests := []struct {
+		message string
+		code    string
+		target  error
+		want    bool
+	}{
+		{"insufficient funds", "", ErrInsufficientFunds, true},
+		{"Insufficient funds", "", ErrInsufficientFunds, true},
+		{"not enough options", "BAD_INPUT", ErrInsufficientFunds, false},
+		{"not enough options", "BAD_INPUT", ErrBadInput, true},
+		{"you must be logged in", "", ErrUnauthenticated, true},
+		{"item not found", "", ErrNotFound, true},
+		{"item can no longer be edited", "", ErrEditWindowClosed, true},
+	}
+	for _, tt := range tests {
+		err := newGraphQLError([]GqlError{{Message: tt.message, Extensions: GqlErrorExtensions{Code: tt.code}}})
+		if got := errors.Is(err, tt.target); got != tt.want {
+			t.Errorf("errors.Is(%q, %v): expected %v, got %v", tt.message, tt.target, tt.want, got)
+		}
+	}
+}
@@ -2,4 +2,4 @@ module github.com/ekzyis/snappy
 
 go 1.20
 
-require gopkg.in/guregu/null.v4 v4.0.0 // indirect
+require gopkg.in/guregu/null.v4 v4.0.0
@@ -1,30 +1,86 @@
 package sn
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"time"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+var (
+	ErrInvoiceCancelled = errors.New("invoice cancelled")
+	ErrInvoiceExpired   = errors.New("invoice expired")
 )
 
+// Invoice is a Lightning invoice created by SN.
+// CreatedAt is only set by Invoice and WaitForInvoice, see the TODO in CreateInvoiceContext.
 type Invoice struct {
-	Id                int                    `json:"id,string"`
-	Hash              string                 `json:"hash"`
-	Hmac              string                 `json:"hmac"`
-	Bolt11            string                 `json:"bolt11"`
-	SatsRequested     int                    `json:"satsRequested"`
-	SatsReceived      int                    `json:"satsReceived"`
-	Cancelled         bool                   `json:"cancelled"`
-	ConfirmedAt       time.Time              `json:"createdAt"`
-	ExpiresAt         time.Time              `json:"expiresAt"`
-	Nostr             map[string]interface{} `json:"nostr"`
-	IsHeld            bool                   `json:"isHeld"`
-	Comment           string                 `json:"comment"`
-	Lud18Data         map[string]interface{} `json:"lud18Data"`
-	ConfirmedPreimage string                 `json:"confirmedPreimage"`
-	ActionState       string                 `json:"actionState"`
-	ActionType        string                 `json:"actionType"`
+	Id                int        `json:"id,string"`
+	Hash              string     `json:"hash"`
+	Hmac              string     `json:"hmac"`
+	Bolt11            string     `json:"bolt11"`
+	SatsRequested     int        `json:"satsRequested"`
+	SatsReceived      int        `json:"satsReceived"`
+	Cancelled         bool       `json:"cancelled"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	ConfirmedAt       null.Time  `json:"confirmedAt"`
+	ExpiresAt         time.Time  `json:"expiresAt"`
+	Nostr             *NostrNote `json:"nostr"`
+	IsHeld            bool       `json:"isHeld"`
+	Comment           string     `json:"comment"`
+	Lud18Data         *Lud18Data `json:"lud18Data"`
+	ConfirmedPreimage string     `json:"confirmedPreimage"`
+	ActionState       string     `json:"actionState"`
+	ActionType        string     `json:"actionType"`
+	Item              *Item      `json:"item"`
+}
+
+// NostrNote is the zap request of a NIP-57 zap.
+type NostrNote struct {
+	Id        string     `json:"id"`
+	Pubkey    string     `json:"pubkey"`
+	CreatedAt int64      `json:"created_at"`
+	Kind      int        `json:"kind"`
+	Tags      [][]string `json:"tags"`
+	Content   string     `json:"content"`
+	Sig       string     `json:"sig"`
+}
+
+// Lud18Data is the payer data sent with a LUD-18 payment.
+type Lud18Data struct {
+	Name       string `json:"name"`
+	Identifier string `json:"identifier"`
+	Email      string `json:"email"`
+	Pubkey     string `json:"pubkey"`
 }
 
+const (
+	ActionStatePending     = "PENDING"
+	ActionStatePendingHeld = "PENDING_HELD"
+	ActionStateHeld        = "HELD"
+	ActionStatePaid        = "PAID"
+	ActionStateFailed      = "FAILED"
+)
+
+// invoiceFields is the fragment used to select invoices of paid actions.
+const invoiceFields = `
+fragment InvoiceFields on Invoice {
+	id
+	hash
+	hmac
+	bolt11
+	satsRequested
+	satsReceived
+	cancelled
+	isHeld
+	expiresAt
+	confirmedAt
+	actionState
+	actionType
+}`
+
 type PaymentMethod string
 
 const (
@@ -39,59 +95,194 @@ type CreateInvoiceArgs struct {
 	HodlInvoice bool
 }
 
-type CreateInvoiceResponse struct {
-	Errors []GqlError `json:"errors"`
-	Data   struct {
-		CreateInvoice Invoice `json:"createInvoice"`
-	} `json:"data"`
+type createInvoiceData struct {
+	CreateInvoice Invoice `json:"createInvoice"`
 }
 
+type CreateInvoiceResponse = GqlResponse[createInvoiceData]
+
 func (c *Client) CreateInvoice(args *CreateInvoiceArgs) (*Invoice, error) {
+	return c.CreateInvoiceContext(context.Background(), args)
+}
+
+func (c *Client) CreateInvoiceContext(ctx context.Context, args *CreateInvoiceArgs) (*Invoice, error) {
 	if args == nil {
 		args = &CreateInvoiceArgs{}
 	}
 
-	body := GqlBody{
-		// TODO: add createdAt
-		//   when I wrote this code, createdAt returned null but is non-nullable
-		//   so I had to remove it.
-		Query: `
-		mutation createInvoice($amount: Int!, $expireSecs: Int, $hodlInvoice: Boolean) {
-			createInvoice(amount: $amount, expireSecs: $expireSecs, hodlInvoice: $hodlInvoice) {
-				id
-				hash
-				hmac
-				bolt11
-				satsRequested
-				satsReceived
-				isHeld
-				comment
-				confirmedPreimage
-				expiresAt
-				confirmedAt
-			}
-		}`,
-		Variables: map[string]interface{}{
-			"amount": args.Amount,
-		},
+	// TODO: add createdAt
+	//   when I wrote this code, createdAt returned null but is non-nullable
+	//   so I had to remove it.
+	query := `
+	mutation createInvoice($amount: Int!, $expireSecs: Int, $hodlInvoice: Boolean) {
+		createInvoice(amount: $amount, expireSecs: $expireSecs, hodlInvoice: $hodlInvoice) {
+			id
+			hash
+			hmac
+			bolt11
+			satsRequested
+			satsReceived
+			isHeld
+			comment
+			confirmedPreimage
+			expiresAt
+			confirmedAt
+		}
+	}`
+	variables := map[string]interface{}{
+		"amount":      args.Amount,
+		"hodlInvoice": args.HodlInvoice,
+	}
+	if args.ExpireSecs > 0 {
+		variables["expireSecs"] = args.ExpireSecs
 	}
 
-	resp, err := c.callApi(body)
+	data, err := Do[createInvoiceData](ctx, c, query, variables)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
+	return &data.CreateInvoice, nil
+}
 
-	var respBody CreateInvoiceResponse
-	err = json.NewDecoder(resp.Body).Decode(&respBody)
+func (c *Client) Invoice(id int) (*Invoice, error) {
+	return c.InvoiceContext(context.Background(), id)
+}
+
+// InvoiceContext fetches the invoice with the given id.
+// No hmac is required since SN only returns invoices of the authenticated user.
+func (c *Client) InvoiceContext(ctx context.Context, id int) (*Invoice, error) {
+	query := itemFields + invoiceFields + `
+	query invoice($id: ID!) {
+		invoice(id: $id) {
+			...InvoiceFields
+			createdAt
+			comment
+			nostr
+			lud18Data
+			confirmedPreimage
+			item {
+				...ItemFields
+			}
+		}
+	}`
+	variables := map[string]interface{}{
+		"id": id,
+	}
+
+	data, err := Do[struct {
+		Invoice Invoice `json:"invoice"`
+	}](ctx, c, query, variables)
 	if err != nil {
-		err = fmt.Errorf("error decoding items: %w", err)
 		return nil, err
 	}
+	return &data.Invoice, nil
+}
+
+type PollOptions struct {
+	// MinInterval is the time to wait after the first poll.
+	// The interval is doubled after every poll up to MaxInterval.
+	MinInterval time.Duration
+	MaxInterval time.Duration
+}
+
+var DefaultPollOptions = PollOptions{
+	MinInterval: time.Second,
+	MaxInterval: 30 * time.Second,
+}
+
+// next returns the interval to wait after a poll that waited interval before.
+// Zero values of MinInterval and MaxInterval are taken from DefaultPollOptions.
+func (o *PollOptions) next(interval time.Duration) time.Duration {
+	minInterval, maxInterval := o.MinInterval, o.MaxInterval
+	if minInterval <= 0 {
+		minInterval = DefaultPollOptions.MinInterval
+	}
+	if maxInterval <= 0 {
+		maxInterval = DefaultPollOptions.MaxInterval
+	}
+	if maxInterval < minInterval {
+		maxInterval = minInterval
+	}
+
+	interval *= 2
+	if interval < minInterval {
+		interval = minInterval
+	}
+	if interval > maxInterval {
+		interval = maxInterval
+	}
+	return interval
+}
+
+func (c *Client) WaitForInvoice(id int, opts *PollOptions) (*Invoice, error) {
+	return c.WaitForInvoiceContext(context.Background(), id, opts)
+}
+
+// WaitForInvoiceContext polls the invoice until it was paid, cancelled or expired.
+// If it was cancelled or expired, the invoice is returned together with ErrInvoiceCancelled or ErrInvoiceExpired.
+// Invoices of paid actions are returned without error once the action was performed or failed.
+func (c *Client) WaitForInvoiceContext(ctx context.Context, id int, opts *PollOptions) (*Invoice, error) {
+	if opts == nil {
+		opts = &DefaultPollOptions
+	}
+
+	var interval time.Duration
+	for {
+		invoice, err := c.InvoiceContext(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		// SN cancels the invoice of a failed action so check this first
+		case invoice.ActionState == ActionStateFailed:
+			return invoice, nil
+		case invoice.Cancelled:
+			return invoice, fmt.Errorf("invoice %d: %w", id, ErrInvoiceCancelled)
+		case invoice.isPaid():
+			return invoice, nil
+		case !invoice.ExpiresAt.IsZero() && time.Now().After(invoice.ExpiresAt):
+			return invoice, fmt.Errorf("invoice %d: %w", id, ErrInvoiceExpired)
+		}
+
+		interval = opts.next(interval)
+		if err = sleep(ctx, interval); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// isPaid returns true if the invoice was paid. For invoices of paid actions,
+// it only returns true once the action was performed or failed.
+func (i *Invoice) isPaid() bool {
+	if i.ActionType != "" {
+		return i.ActionState == ActionStatePaid || i.ActionState == ActionStateFailed
+	}
+	return i.ConfirmedAt.Valid || i.IsHeld || i.SatsReceived > 0
+}
+
+func (c *Client) CancelInvoice(hash string, hmac string) (*Invoice, error) {
+	return c.CancelInvoiceContext(context.Background(), hash, hmac)
+}
+
+// CancelInvoiceContext cancels an invoice that was not paid yet or a HODL invoice that is held.
+func (c *Client) CancelInvoiceContext(ctx context.Context, hash string, hmac string) (*Invoice, error) {
+	query := invoiceFields + `
+	mutation cancelInvoice($hash: String!, $hmac: String) {
+		cancelInvoice(hash: $hash, hmac: $hmac) {
+			...InvoiceFields
+		}
+	}`
+	variables := map[string]interface{}{
+		"hash": hash,
+		"hmac": hmac,
+	}
 
-	err = c.checkForErrors(respBody.Errors)
+	data, err := Do[struct {
+		CancelInvoice Invoice `json:"cancelInvoice"`
+	}](ctx, c, query, variables)
 	if err != nil {
 		return nil, err
 	}
-	return &respBody.Data.CreateInvoice, nil
+	return &data.CancelInvoice, nil
 }
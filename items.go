@@ -1,7 +1,7 @@
 package sn
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"time"
 
@@ -20,13 +20,19 @@ type Item struct {
 	Comments  []Comment `json:"comments"`
 	NComments int       `json:"ncomments"`
 	User      User      `json:"user"`
+	// SearchTitle and SearchText contain highlighted snippets if the item was returned by Search.
+	SearchTitle string `json:"searchTitle"`
+	SearchText  string `json:"searchText"`
 }
 
 type Comment struct {
 	Id        int       `json:"id,string"`
 	ParentId  int       `json:"parentId"`
 	CreatedAt time.Time `json:"createdAt"`
+	DeletedAt null.Time `json:"deletedAt"`
 	Text      string    `json:"text"`
+	Sats      int       `json:"sats"`
+	NComments int       `json:"ncomments"`
 	User      User      `json:"user"`
 	Comments  []Comment `json:"comments"`
 }
@@ -47,45 +53,16 @@ type ItemsCursor struct {
 	Cursor string `json:"cursor"`
 }
 
-type ItemResponse struct {
-	Errors []GqlError `json:"errors"`
-	Data   struct {
-		Item Item `json:"item"`
-	} `json:"data"`
-}
-
-type ItemsResponse struct {
-	Errors []GqlError `json:"errors"`
-	Data   struct {
-		Items ItemsCursor `json:"items"`
-	} `json:"data"`
-}
-
 type ItemPaidAction struct {
 	Result        Item          `json:"result"`
 	Invoice       Invoice       `json:"invoice"`
 	PaymentMethod PaymentMethod `json:"paymentMethod"`
 }
 
-type UpsertDiscussionResponse struct {
-	Errors []GqlError `json:"errors"`
-	Data   struct {
-		UpsertDiscussion ItemPaidAction `json:"upsertDiscussion"`
-	} `json:"data"`
-}
-
-type UpsertLinkResponse struct {
-	Errors []GqlError `json:"errors"`
-	Data   struct {
-		UpsertLink ItemPaidAction `json:"upsertLink"`
-	} `json:"data"`
-}
-
-type UpsertCommentResponse struct {
-	Errors []GqlError `json:"errors"`
-	Data   struct {
-		UpsertComment ItemPaidAction `json:"upsertComment"`
-	} `json:"data"`
+// IsPessimistic returns true if the action was not performed yet
+// because its invoice must be paid first.
+func (a *ItemPaidAction) IsPessimistic() bool {
+	return a.PaymentMethod == PaymentMethodPessimistic
 }
 
 type Dupe struct {
@@ -98,13 +75,6 @@ type Dupe struct {
 	NComments int       `json:"ncomments"`
 }
 
-type DupesResponse struct {
-	Errors []GqlError `json:"errors"`
-	Data   struct {
-		Dupes []Dupe `json:"dupes"`
-	} `json:"data"`
-}
-
 type DupesError struct {
 	Url   string
 	Dupes []Dupe
@@ -114,11 +84,87 @@ func (e *DupesError) Error() string {
 	return fmt.Sprintf("found %d dupes for %s", len(e.Dupes), e.Url)
 }
 
+// itemFields is the fragment used to select items returned by mutations.
+const itemFields = `
+fragment ItemFields on Item {
+	id
+	parentId
+	title
+	url
+	text
+	sats
+	createdAt
+	deletedAt
+	ncomments
+	user {
+		id
+		name
+	}
+}`
+
+type itemData struct {
+	Item Item `json:"item"`
+}
+
+type ItemResponse = GqlResponse[itemData]
+
 func (c *Client) Item(id int) (*Item, error) {
-	body := GqlBody{
-		Query: `
-		query item($id: ID!) {
-			item(id: $id) {
+	return c.ItemContext(context.Background(), id)
+}
+
+func (c *Client) ItemContext(ctx context.Context, id int) (*Item, error) {
+	query := `
+	query item($id: ID!) {
+		item(id: $id) {
+			id
+			parentId
+			title
+			url
+			text
+			sats
+			createdAt
+			deletedAt
+			ncomments
+			user {
+				id
+				name
+			}
+		}
+	}`
+	variables := map[string]interface{}{
+		"id": id,
+	}
+
+	data, err := Do[itemData](ctx, c, query, variables)
+	if err != nil {
+		return nil, err
+	}
+	if data.Item.Id == 0 {
+		return nil, fmt.Errorf("item %d: %w", id, ErrNotFound)
+	}
+	return &data.Item, nil
+}
+
+type itemsData struct {
+	Items ItemsCursor `json:"items"`
+}
+
+type ItemsResponse = GqlResponse[itemsData]
+
+func (c *Client) Items(query *ItemsQuery) (*ItemsCursor, error) {
+	return c.ItemsContext(context.Background(), query)
+}
+
+func (c *Client) ItemsContext(ctx context.Context, q *ItemsQuery) (*ItemsCursor, error) {
+	if q == nil {
+		q = &ItemsQuery{}
+	}
+
+	query := `
+	query items($sub: String, $sort: String, $cursor: String, $type: String, $name: String, $when: String, $by: String, $limit: Limit) {
+		items(sub: $sub, sort: $sort, cursor: $cursor, type: $type, name: $name, when: $when, by: $by, limit: $limit) {
+			cursor
+			items {
 				id
 				parentId
 				title
@@ -132,246 +178,306 @@ func (c *Client) Item(id int) (*Item, error) {
 					id
 					name
 				}
-			}
-		}`,
-		Variables: map[string]interface{}{
-			"id": id,
-		},
+			},
+		}
+	}`
+	variables := map[string]interface{}{
+		"sub":    q.Sub,
+		"sort":   q.Sort,
+		"type":   q.Type,
+		"cursor": q.Cursor,
+		"name":   q.Name,
+		"when":   q.When,
+		"by":     q.By,
+		"limit":  q.Limit,
+	}
+	if q.Limit == 0 {
+		variables["limit"] = 21
 	}
 
-	resp, err := c.callApi(body)
+	data, err := Do[itemsData](ctx, c, query, variables)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
+	return &data.Items, nil
+}
 
-	var respBody ItemResponse
-	err = json.NewDecoder(resp.Body).Decode(&respBody)
-	if err != nil {
-		err = fmt.Errorf("error decoding item: %w", err)
+func (c *Client) PostDiscussion(title string, text string, sub string, opts ...PostOptions) (*ItemPaidAction, error) {
+	return c.PostDiscussionContext(context.Background(), title, text, sub, opts...)
+}
+
+func (c *Client) PostDiscussionContext(ctx context.Context, title string, text string, sub string, opts ...PostOptions) (*ItemPaidAction, error) {
+	var v ValidationError
+	o := postOptions(&v, opts)
+	o.validate(&v)
+	if err := v.err(); err != nil {
 		return nil, err
 	}
 
-	err = c.checkForErrors(respBody.Errors)
-	if err != nil {
-		return nil, err
+	variables := map[string]interface{}{
+		"title": title,
+		"text":  text,
+		"sub":   sub,
 	}
-	return &respBody.Data.Item, nil
+	o.addVariables(variables)
+
+	return c.upsertDiscussion(ctx, variables)
 }
 
-func (c *Client) Items(query *ItemsQuery) (*ItemsCursor, error) {
-	if query == nil {
-		query = &ItemsQuery{}
-	}
+func (c *Client) EditDiscussion(id int, title string, text string, sub string, opts ...PostOptions) (*ItemPaidAction, error) {
+	return c.EditDiscussionContext(context.Background(), id, title, text, sub, opts...)
+}
 
-	body := GqlBody{
-		Query: `
-		query items($sub: String, $sort: String, $cursor: String, $type: String, $name: String, $when: String, $by: String, $limit: Limit) {
-			items(sub: $sub, sort: $sort, cursor: $cursor, type: $type, name: $name, when: $when, by: $by, limit: $limit) {
-				cursor
-				items {
-					id
-					parentId
-					title
-					url
-					text
-					sats
-					createdAt
-					deletedAt
-					ncomments
-					user {
-						id
-						name
-					}
-				},
-			}
-		}`,
-		Variables: map[string]interface{}{
-			"sub":    query.Sub,
-			"sort":   query.Sort,
-			"type":   query.Type,
-			"cursor": query.Cursor,
-			"name":   query.Name,
-			"when":   query.When,
-			"by":     query.By,
-			"limit":  query.Limit,
-		},
+// EditDiscussionContext edits the discussion. Boost and forwards are only changed if options are passed.
+func (c *Client) EditDiscussionContext(ctx context.Context, id int, title string, text string, sub string, opts ...PostOptions) (*ItemPaidAction, error) {
+	var v ValidationError
+	o := postOptions(&v, opts)
+	o.validate(&v)
+	if err := v.err(); err != nil {
+		return nil, err
 	}
-	if query.Limit == 0 {
-		body.Variables["limit"] = 21
+
+	variables := map[string]interface{}{
+		"id":    id,
+		"title": title,
+		"text":  text,
+		"sub":   sub,
 	}
+	o.addVariables(variables)
+
+	return c.upsertDiscussion(ctx, variables)
+}
+
+type upsertDiscussionData struct {
+	UpsertDiscussion ItemPaidAction `json:"upsertDiscussion"`
+}
 
-	resp, err := c.callApi(body)
+type UpsertDiscussionResponse = GqlResponse[upsertDiscussionData]
+
+func (c *Client) upsertDiscussion(ctx context.Context, variables map[string]interface{}) (*ItemPaidAction, error) {
+	query := itemFields + invoiceFields + `
+	mutation upsertDiscussion($id: ID, $title: String!, $text: String, $sub: String, $boost: Int, $forward: [ItemForwardInput]) {
+		upsertDiscussion(id: $id, title: $title, text: $text, sub: $sub, boost: $boost, forward: $forward) {
+			result {
+				...ItemFields
+			}
+			invoice {
+				...InvoiceFields
+			}
+			paymentMethod
+		}
+	}`
+
+	data, err := Do[upsertDiscussionData](ctx, c, query, variables)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	var respBody ItemsResponse
-	err = json.NewDecoder(resp.Body).Decode(&respBody)
-	if err != nil {
-		err = fmt.Errorf("error decoding items: %w", err)
+	return &data.UpsertDiscussion, nil
+}
+
+func (c *Client) PostLink(url string, title string, text string, sub string, opts ...PostOptions) (*ItemPaidAction, error) {
+	return c.PostLinkContext(context.Background(), url, title, text, sub, opts...)
+}
+
+func (c *Client) PostLinkContext(ctx context.Context, url string, title string, text string, sub string, opts ...PostOptions) (*ItemPaidAction, error) {
+	var v ValidationError
+	o := postOptions(&v, opts)
+	o.validate(&v)
+	if err := v.err(); err != nil {
 		return nil, err
 	}
 
-	err = c.checkForErrors(respBody.Errors)
-	if err != nil {
-		return nil, err
+	variables := map[string]interface{}{
+		"url":   url,
+		"title": title,
+		"text":  text,
+		"sub":   sub,
 	}
-	return &respBody.Data.Items, nil
+	o.addVariables(variables)
+
+	return c.upsertLink(ctx, variables)
 }
 
-func (c *Client) PostDiscussion(title string, text string, sub string) (int, error) {
-	body := GqlBody{
-		Query: `
-		mutation upsertDiscussion($title: String!, $text: String, $sub: String) {
-			upsertDiscussion(title: $title, text: $text, sub: $sub) {
-				result { id }
-			}
-		}`,
-		Variables: map[string]interface{}{
-			"title": title,
-			"text":  text,
-			"sub":   sub,
-		},
-	}
+func (c *Client) EditLink(id int, url string, title string, text string, sub string, opts ...PostOptions) (*ItemPaidAction, error) {
+	return c.EditLinkContext(context.Background(), id, url, title, text, sub, opts...)
+}
 
-	resp, err := c.callApi(body)
-	if err != nil {
-		return -1, err
+// EditLinkContext edits the link. Boost and forwards are only changed if options are passed.
+func (c *Client) EditLinkContext(ctx context.Context, id int, url string, title string, text string, sub string, opts ...PostOptions) (*ItemPaidAction, error) {
+	var v ValidationError
+	o := postOptions(&v, opts)
+	o.validate(&v)
+	if err := v.err(); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	var respBody UpsertDiscussionResponse
-	err = json.NewDecoder(resp.Body).Decode(&respBody)
-	if err != nil {
-		err = fmt.Errorf("error decoding upsertDiscussion: %w", err)
-		return -1, err
+	variables := map[string]interface{}{
+		"id":    id,
+		"url":   url,
+		"title": title,
+		"text":  text,
+		"sub":   sub,
 	}
+	o.addVariables(variables)
 
-	err = c.checkForErrors(respBody.Errors)
-	if err != nil {
-		return -1, err
-	}
+	return c.upsertLink(ctx, variables)
+}
 
-	return respBody.Data.UpsertDiscussion.Result.Id, nil
+type upsertLinkData struct {
+	UpsertLink ItemPaidAction `json:"upsertLink"`
 }
 
-func (c *Client) PostLink(url string, title string, text string, sub string) (int, error) {
-	body := GqlBody{
-		Query: `
-		mutation upsertLink($url: String!, $title: String!, $text: String, $sub: String!) {
-			upsertLink(url: $url, title: $title, text: $text, sub: $sub) {
-				result { id }
+type UpsertLinkResponse = GqlResponse[upsertLinkData]
+
+func (c *Client) upsertLink(ctx context.Context, variables map[string]interface{}) (*ItemPaidAction, error) {
+	query := itemFields + invoiceFields + `
+	mutation upsertLink($id: ID, $url: String!, $title: String!, $text: String, $sub: String!, $boost: Int, $forward: [ItemForwardInput]) {
+		upsertLink(id: $id, url: $url, title: $title, text: $text, sub: $sub, boost: $boost, forward: $forward) {
+			result {
+				...ItemFields
 			}
-		}`,
-		Variables: map[string]interface{}{
-			"url":   url,
-			"title": title,
-			"text":  text,
-			"sub":   sub,
-		},
-	}
+			invoice {
+				...InvoiceFields
+			}
+			paymentMethod
+		}
+	}`
 
-	resp, err := c.callApi(body)
+	data, err := Do[upsertLinkData](ctx, c, query, variables)
 	if err != nil {
-		return -1, err
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	var respBody UpsertLinkResponse
-	err = json.NewDecoder(resp.Body).Decode(&respBody)
-	if err != nil {
-		err = fmt.Errorf("error decoding upsertLink: %w", err)
-		return -1, err
-	}
+	return &data.UpsertLink, nil
+}
 
-	err = c.checkForErrors(respBody.Errors)
-	if err != nil {
-		return -1, err
-	}
+func (c *Client) CreateComment(parentId int, text string) (*ItemPaidAction, error) {
+	return c.CreateCommentContext(context.Background(), parentId, text)
+}
+
+func (c *Client) CreateCommentContext(ctx context.Context, parentId int, text string) (*ItemPaidAction, error) {
+	return c.upsertComment(ctx, map[string]interface{}{
+		"parentId": parentId,
+		"text":     text,
+	})
+}
 
-	return respBody.Data.UpsertLink.Result.Id, nil
+func (c *Client) EditComment(id int, text string) (*ItemPaidAction, error) {
+	return c.EditCommentContext(context.Background(), id, text)
 }
 
-func (c *Client) CreateComment(parentId int, text string) (int, error) {
-	body := GqlBody{
-		Query: `
-		mutation upsertComment($parentId: ID!, $text: String!) {
-			upsertComment(parentId: $parentId, text: $text) {
-				result { id }
+func (c *Client) EditCommentContext(ctx context.Context, id int, text string) (*ItemPaidAction, error) {
+	return c.upsertComment(ctx, map[string]interface{}{
+		"id":   id,
+		"text": text,
+	})
+}
+
+type upsertCommentData struct {
+	UpsertComment ItemPaidAction `json:"upsertComment"`
+}
+
+type UpsertCommentResponse = GqlResponse[upsertCommentData]
+
+func (c *Client) upsertComment(ctx context.Context, variables map[string]interface{}) (*ItemPaidAction, error) {
+	query := itemFields + invoiceFields + `
+	mutation upsertComment($id: ID, $parentId: ID, $text: String!) {
+		upsertComment(id: $id, parentId: $parentId, text: $text) {
+			result {
+				...ItemFields
 			}
-		}`,
-		Variables: map[string]interface{}{
-			"parentId": parentId,
-			"text":     text,
-		},
-	}
+			invoice {
+				...InvoiceFields
+			}
+			paymentMethod
+		}
+	}`
 
-	resp, err := c.callApi(body)
+	data, err := Do[upsertCommentData](ctx, c, query, variables)
 	if err != nil {
-		return -1, err
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	var respBody UpsertCommentResponse
-	err = json.NewDecoder(resp.Body).Decode(&respBody)
-	if err != nil {
-		err = fmt.Errorf("error decoding upsertComment: %w", err)
-		return -1, err
+	return &data.UpsertComment, nil
+}
+
+func (c *Client) DeleteItem(id int) (*Item, error) {
+	return c.DeleteItemContext(context.Background(), id)
+}
+
+func (c *Client) DeleteItemContext(ctx context.Context, id int) (*Item, error) {
+	query := itemFields + `
+	mutation deleteItem($id: ID!) {
+		deleteItem(id: $id) {
+			...ItemFields
+		}
+	}`
+	variables := map[string]interface{}{
+		"id": id,
 	}
 
-	err = c.checkForErrors(respBody.Errors)
+	data, err := Do[struct {
+		DeleteItem Item `json:"deleteItem"`
+	}](ctx, c, query, variables)
 	if err != nil {
-		return -1, err
+		return nil, err
 	}
 
-	return respBody.Data.UpsertComment.Result.Id, nil
+	return &data.DeleteItem, nil
+}
+
+// EditWindow is the time after creation during which SN allows items to be edited.
+// Edits after this window fail with ErrEditWindowClosed.
+const EditWindow = 10 * time.Minute
+
+func (i *Item) EditableUntil() time.Time {
+	return i.CreatedAt.Add(EditWindow)
+}
+
+type dupesData struct {
+	Dupes []Dupe `json:"dupes"`
 }
 
+type DupesResponse = GqlResponse[dupesData]
+
 func (c *Client) Dupes(url string) (*[]Dupe, error) {
-	body := GqlBody{
-		Query: `
-		query Dupes($url: String!) {
-			dupes(url: $url) {
-				id
-				url
-				title
-				user {
-					name
-				}
-				createdAt
-				sats
-				ncomments
-			}
-		}`,
-		Variables: map[string]interface{}{
-			"url": url,
-		},
-	}
-	resp, err := c.callApi(body)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+	return c.DupesContext(context.Background(), url)
+}
 
-	var respBody DupesResponse
-	err = json.NewDecoder(resp.Body).Decode(&respBody)
-	if err != nil {
-		err = fmt.Errorf("error decoding dupes: %w", err)
-		return nil, err
+func (c *Client) DupesContext(ctx context.Context, url string) (*[]Dupe, error) {
+	query := `
+	query Dupes($url: String!) {
+		dupes(url: $url) {
+			id
+			url
+			title
+			user {
+				name
+			}
+			createdAt
+			sats
+			ncomments
+		}
+	}`
+	variables := map[string]interface{}{
+		"url": url,
 	}
 
-	err = c.checkForErrors(respBody.Errors)
+	data, err := Do[dupesData](ctx, c, query, variables)
 	if err != nil {
 		return nil, err
 	}
 
-	return &respBody.Data.Dupes, nil
+	return &data.Dupes, nil
 }
 
 func (c *Client) HasDupes(url string) (bool, error) {
-	dupes, err := c.Dupes(url)
+	return c.HasDupesContext(context.Background(), url)
+}
+
+func (c *Client) HasDupesContext(ctx context.Context, url string) (bool, error) {
+	dupes, err := c.DupesContext(ctx, url)
 	if err != nil {
 		return false, err
 	}
@@ -0,0 +1,124 @@
+package sn
+
+import (
+	"context"
+	"time"
+)
+
+type ItemsIterOptions struct {
+	// MaxItems stops the iteration after this many items. Zero means no limit.
+	MaxItems int
+	// MaxDuration stops the iteration before fetching the next page
+	// if this much time has passed since the first page was fetched. Zero means no limit.
+	MaxDuration time.Duration
+}
+
+// ItemsIter walks all pages of an items query.
+// Items that appear on multiple pages are only returned once.
+//
+//	it := c.ItemsIter(&sn.ItemsQuery{Sub: "bitcoin", Sort: "recent"}, nil)
+//	for it.Next(ctx) {
+//		item := it.Item()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type ItemsIter struct {
+	c     *Client
+	query ItemsQuery
+	opts  ItemsIterOptions
+	start time.Time
+	page  []Item
+	item  Item
+	seen  map[int]struct{}
+	count int
+	done  bool
+	err   error
+}
+
+func (c *Client) ItemsIter(query *ItemsQuery, opts *ItemsIterOptions) *ItemsIter {
+	it := &ItemsIter{c: c, seen: make(map[int]struct{})}
+	if query != nil {
+		it.query = *query
+	}
+	if opts != nil {
+		it.opts = *opts
+	}
+	return it
+}
+
+// Next advances the iterator to the next item and fetches the next page if required.
+// It returns false when all pages were walked, a limit was reached or an error occurred.
+func (it *ItemsIter) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	if err := ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	for {
+		if it.opts.MaxItems > 0 && it.count >= it.opts.MaxItems {
+			return false
+		}
+
+		for len(it.page) > 0 {
+			item := it.page[0]
+			it.page = it.page[1:]
+			if _, ok := it.seen[item.Id]; ok {
+				continue
+			}
+			it.seen[item.Id] = struct{}{}
+			it.item = item
+			it.count++
+			return true
+		}
+
+		if it.done {
+			return false
+		}
+
+		if it.start.IsZero() {
+			it.start = time.Now()
+		} else if it.opts.MaxDuration > 0 && time.Since(it.start) >= it.opts.MaxDuration {
+			return false
+		}
+
+		cursor, err := it.c.ItemsContext(ctx, &it.query)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.page = cursor.Items
+		it.query.Cursor = cursor.Cursor
+		if cursor.Cursor == "" || len(cursor.Items) == 0 {
+			it.done = true
+		}
+	}
+}
+
+func (it *ItemsIter) Item() Item {
+	return it.item
+}
+
+func (it *ItemsIter) Err() error {
+	return it.err
+}
+
+func (c *Client) AllItems(query *ItemsQuery, opts *ItemsIterOptions) ([]Item, error) {
+	return c.AllItemsContext(context.Background(), query, opts)
+}
+
+// AllItemsContext collects the items of all pages. The items collected so far are returned
+// together with the error if the iteration was stopped because of an error.
+func (c *Client) AllItemsContext(ctx context.Context, query *ItemsQuery, opts *ItemsIterOptions) ([]Item, error) {
+	var items []Item
+	it := c.ItemsIter(query, opts)
+	for it.Next(ctx) {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
@@ -1,15 +1,248 @@
 package sn
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 )
 
+const (
+	NotificationTypeReply                 = "Reply"
+	NotificationTypeMention               = "Mention"
+	NotificationTypeItemMention           = "ItemMention"
+	NotificationTypeVotification          = "Votification"
+	NotificationTypeForwardedVotification = "ForwardedVotification"
+	NotificationTypeEarn                  = "Earn"
+	NotificationTypeRevenue               = "Revenue"
+	NotificationTypeReferralReward        = "ReferralReward"
+	NotificationTypeInvitification        = "Invitification"
+	NotificationTypeReferral              = "Referral"
+	NotificationTypeStreak                = "Streak"
+	NotificationTypeFollowActivity        = "FollowActivity"
+	NotificationTypeTerritoryPost         = "TerritoryPost"
+	NotificationTypeTerritoryTransfer     = "TerritoryTransfer"
+	NotificationTypeJobChanged            = "JobChanged"
+	NotificationTypeInvoicePaid           = "InvoicePaid"
+	NotificationTypeWithdrawlPaid         = "WithdrawlPaid"
+)
+
+// Notification contains the fields that all notifications share.
+// The typed payload depends on Type and is one of the types below
+// or *UnknownNotification if this package does not know the type yet.
 type Notification struct {
-	Id   int    `json:"id,string"`
-	Type string `json:"__typename"`
-	Item Item   `json:"item"`
+	Id       int
+	Type     string
+	SortTime time.Time
+	// Item is set if the notification is about an item.
+	Item    Item
+	Payload NotificationPayload
+	Raw     json.RawMessage
+}
+
+// NotificationPayload is implemented by all notification types of this package.
+type NotificationPayload interface {
+	notificationType() string
+}
+
+type Reply struct {
+	Item Item `json:"item"`
+}
+
+type Mention struct {
+	Item Item `json:"item"`
+}
+
+type ItemMention struct {
+	Item Item `json:"item"`
+}
+
+type Votification struct {
+	EarnedSats int  `json:"earnedSats"`
+	Item       Item `json:"item"`
+}
+
+type ForwardedVotification struct {
+	EarnedSats int  `json:"earnedSats"`
+	Item       Item `json:"item"`
+}
+
+type Earn struct {
+	EarnedSats  int         `json:"earnedSats"`
+	MinSortTime time.Time   `json:"minSortTime"`
+	Sources     EarnSources `json:"sources"`
+}
+
+type EarnSources struct {
+	Posts       int `json:"posts"`
+	Comments    int `json:"comments"`
+	TipPosts    int `json:"tipPosts"`
+	TipComments int `json:"tipComments"`
+}
+
+type Revenue struct {
+	EarnedSats int    `json:"earnedSats"`
+	SubName    string `json:"subName"`
+}
+
+type ReferralReward struct {
+	EarnedSats int             `json:"earnedSats"`
+	Sources    ReferralSources `json:"sources"`
+}
+
+type ReferralSources struct {
+	Forever int `json:"forever"`
+	OneDay  int `json:"oneDay"`
+}
+
+type Invitification struct {
+	Invite Invite `json:"invite"`
+}
+
+type Invite struct {
+	Id      string `json:"id"`
+	Gift    int    `json:"gift"`
+	Limit   int    `json:"limit"`
+	Revoked bool   `json:"revoked"`
+}
+
+type Referral struct{}
+
+type Streak struct {
+	Days int `json:"days"`
+}
+
+type FollowActivity struct {
+	Item Item `json:"item"`
+}
+
+type TerritoryPost struct {
+	Item Item `json:"item"`
+}
+
+type TerritoryTransfer struct {
+	Sub Sub `json:"sub"`
+}
+
+type JobChanged struct {
+	Item Item `json:"item"`
+}
+
+type InvoicePaid struct {
+	EarnedSats int     `json:"earnedSats"`
+	Invoice    Invoice `json:"invoice"`
+}
+
+type WithdrawlPaid struct {
+	EarnedSats int       `json:"earnedSats"`
+	Withdrawl  Withdrawl `json:"withdrawl"`
+}
+
+// UnknownNotification is used for notification types this package does not know.
+// The notification is preserved as raw JSON in Notification.Raw.
+type UnknownNotification struct {
+	Type string
+}
+
+func (*Reply) notificationType() string                 { return NotificationTypeReply }
+func (*Mention) notificationType() string               { return NotificationTypeMention }
+func (*ItemMention) notificationType() string           { return NotificationTypeItemMention }
+func (*Votification) notificationType() string          { return NotificationTypeVotification }
+func (*ForwardedVotification) notificationType() string { return NotificationTypeForwardedVotification }
+func (*Earn) notificationType() string                  { return NotificationTypeEarn }
+func (*Revenue) notificationType() string               { return NotificationTypeRevenue }
+func (*ReferralReward) notificationType() string        { return NotificationTypeReferralReward }
+func (*Invitification) notificationType() string        { return NotificationTypeInvitification }
+func (*Referral) notificationType() string              { return NotificationTypeReferral }
+func (*Streak) notificationType() string                { return NotificationTypeStreak }
+func (*FollowActivity) notificationType() string        { return NotificationTypeFollowActivity }
+func (*TerritoryPost) notificationType() string         { return NotificationTypeTerritoryPost }
+func (*TerritoryTransfer) notificationType() string     { return NotificationTypeTerritoryTransfer }
+func (*JobChanged) notificationType() string            { return NotificationTypeJobChanged }
+func (*InvoicePaid) notificationType() string           { return NotificationTypeInvoicePaid }
+func (*WithdrawlPaid) notificationType() string         { return NotificationTypeWithdrawlPaid }
+func (n *UnknownNotification) notificationType() string { return n.Type }
+
+func newNotificationPayload(typename string) NotificationPayload {
+	switch typename {
+	case NotificationTypeReply:
+		return &Reply{}
+	case NotificationTypeMention:
+		return &Mention{}
+	case NotificationTypeItemMention:
+		return &ItemMention{}
+	case NotificationTypeVotification:
+		return &Votification{}
+	case NotificationTypeForwardedVotification:
+		return &ForwardedVotification{}
+	case NotificationTypeEarn:
+		return &Earn{}
+	case NotificationTypeRevenue:
+		return &Revenue{}
+	case NotificationTypeReferralReward:
+		return &ReferralReward{}
+	case NotificationTypeInvitification:
+		return &Invitification{}
+	case NotificationTypeReferral:
+		return &Referral{}
+	case NotificationTypeStreak:
+		return &Streak{}
+	case NotificationTypeFollowActivity:
+		return &FollowActivity{}
+	case NotificationTypeTerritoryPost:
+		return &TerritoryPost{}
+	case NotificationTypeTerritoryTransfer:
+		return &TerritoryTransfer{}
+	case NotificationTypeJobChanged:
+		return &JobChanged{}
+	case NotificationTypeInvoicePaid:
+		return &InvoicePaid{}
+	case NotificationTypeWithdrawlPaid:
+		return &WithdrawlPaid{}
+	}
+	return &UnknownNotification{Type: typename}
+}
+
+func (n *Notification) UnmarshalJSON(data []byte) error {
+	var common struct {
+		Id       json.RawMessage `json:"id"`
+		Type     string          `json:"__typename"`
+		SortTime time.Time       `json:"sortTime"`
+		Item     *Item           `json:"item"`
+	}
+	if err := json.Unmarshal(data, &common); err != nil {
+		return err
+	}
+
+	*n = Notification{
+		Id:       notificationId(common.Id),
+		Type:     common.Type,
+		SortTime: common.SortTime,
+		Raw:      append(json.RawMessage(nil), data...),
+	}
+	if common.Item != nil {
+		n.Item = *common.Item
+	}
+
+	n.Payload = newNotificationPayload(common.Type)
+	if _, ok := n.Payload.(*UnknownNotification); ok {
+		return nil
+	}
+	if err := json.Unmarshal(data, n.Payload); err != nil {
+		return fmt.Errorf("error decoding %s notification: %w", common.Type, err)
+	}
+	return nil
+}
+
+// notificationId returns 0 for notifications that don't have a numeric id like invites.
+func notificationId(raw json.RawMessage) int {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		s = string(raw)
+	}
+	id, _ := strconv.Atoi(s)
+	return id
 }
 
 type NotificationsCursor struct {
@@ -18,100 +251,327 @@ type NotificationsCursor struct {
 	Notifications []Notification `json:"notifications"`
 }
 
-type NotificationsResponse struct {
-	Errors []GqlError `json:"errors"`
-	Data   struct {
-		Notifications NotificationsCursor `json:"notifications"`
-	} `json:"data"`
+const notificationFields = `
+fragment NotificationFields on Notification {
+	__typename
+	... on Reply {
+		id
+		sortTime
+		item {
+			...ItemFields
+		}
+	}
+	... on Mention {
+		id
+		sortTime
+		item {
+			...ItemFields
+		}
+	}
+	... on ItemMention {
+		id
+		sortTime
+		item {
+			...ItemFields
+		}
+	}
+	... on Votification {
+		id
+		sortTime
+		earnedSats
+		item {
+			...ItemFields
+		}
+	}
+	... on ForwardedVotification {
+		id
+		sortTime
+		earnedSats
+		item {
+			...ItemFields
+		}
+	}
+	... on Earn {
+		id
+		sortTime
+		minSortTime
+		earnedSats
+		sources {
+			posts
+			comments
+			tipPosts
+			tipComments
+		}
+	}
+	... on Revenue {
+		id
+		sortTime
+		earnedSats
+		subName
+	}
+	... on ReferralReward {
+		id
+		sortTime
+		earnedSats
+		sources {
+			forever
+			oneDay
+		}
+	}
+	... on Invitification {
+		id
+		sortTime
+		invite {
+			id
+			gift
+			limit
+			revoked
+		}
+	}
+	... on Referral {
+		id
+		sortTime
+	}
+	... on Streak {
+		id
+		sortTime
+		days
+	}
+	... on FollowActivity {
+		id
+		sortTime
+		item {
+			...ItemFields
+		}
+	}
+	... on TerritoryPost {
+		id
+		sortTime
+		item {
+			...ItemFields
+		}
+	}
+	... on TerritoryTransfer {
+		id
+		sortTime
+		sub {
+			name
+		}
+	}
+	... on JobChanged {
+		id
+		sortTime
+		item {
+			...ItemFields
+		}
+	}
+	... on InvoicePaid {
+		id
+		sortTime
+		earnedSats
+		invoice {
+			id
+			comment
+			nostr
+			lud18Data
+		}
+	}
+	... on WithdrawlPaid {
+		id
+		sortTime
+		earnedSats
+		withdrawl {
+			id
+			autoWithdraw
+		}
+	}
+}`
+
+type NotificationsQuery struct {
+	Cursor string
+	// Inc is passed as is to the inc argument of the notifications query.
+	Inc string
+}
+
+type notificationsData struct {
+	Notifications NotificationsCursor `json:"notifications"`
 }
 
+type NotificationsResponse = GqlResponse[notificationsData]
+
 func (c *Client) Notifications() (*NotificationsCursor, error) {
-	body := GqlBody{
-		Query: `
-		fragment ItemFields on Item {
-			id
-			user {
-				id
-				name
-			}
-			parentId
-			createdAt
-			deletedAt
-			title
-			text
-		}
-		query notifications {
+	return c.NotificationsContext(context.Background())
+}
+
+// NotificationsContext fetches the first page of notifications which marks all notifications as read.
+func (c *Client) NotificationsContext(ctx context.Context) (*NotificationsCursor, error) {
+	return c.NotificationsPageContext(ctx, nil)
+}
+
+func (c *Client) NotificationsPage(query *NotificationsQuery) (*NotificationsCursor, error) {
+	return c.NotificationsPageContext(context.Background(), query)
+}
+
+// NotificationsPageContext fetches the page of notifications at the cursor of the query.
+// Fetching the first page marks all notifications as read.
+func (c *Client) NotificationsPageContext(ctx context.Context, q *NotificationsQuery) (*NotificationsCursor, error) {
+	if q == nil {
+		q = &NotificationsQuery{}
+	}
+
+	query := itemFields + notificationFields + `
+	query notifications($cursor: String, $inc: String) {
+		notifications(cursor: $cursor, inc: $inc) {
+			lastChecked
+			cursor
 			notifications {
-				lastChecked
-				cursor
-				notifications {
-					__typename
-					... on Reply {
-						id
-						item {
-							...ItemFields
-						}
-					}
-					... on Mention {
-						id
-						item {
-							...ItemFields
-						}
-					}
-				}
+				...NotificationFields
 			}
 		}
-		`,
-		Variables: map[string]interface{}{},
+	}`
+	variables := map[string]interface{}{}
+	if q.Cursor != "" {
+		variables["cursor"] = q.Cursor
+	}
+	if q.Inc != "" {
+		variables["inc"] = q.Inc
 	}
 
-	resp, err := c.callApi(body)
+	data, err := Do[notificationsData](ctx, c, query, variables)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
+	return &data.Notifications, nil
+}
 
-	var respBody NotificationsResponse
-	err = json.NewDecoder(resp.Body).Decode(&respBody)
-	if err != nil {
-		err = fmt.Errorf("error decoding notifications: %w", err)
-		return nil, err
+func (c *Client) NotificationsSince(since time.Time) ([]Notification, error) {
+	return c.NotificationsSinceContext(context.Background(), since)
+}
+
+// NotificationsSinceContext walks back through all pages until it finds
+// a notification that is not newer than since. The notifications are returned newest first.
+func (c *Client) NotificationsSinceContext(ctx context.Context, since time.Time) ([]Notification, error) {
+	return c.notificationsSince(ctx, func(*NotificationsCursor) time.Time { return since })
+}
+
+func (c *Client) NewNotifications() ([]Notification, error) {
+	return c.NewNotificationsContext(context.Background())
+}
+
+// NewNotificationsContext returns all notifications since the last time notifications were checked.
+func (c *Client) NewNotificationsContext(ctx context.Context) ([]Notification, error) {
+	return c.notificationsSince(ctx, func(n *NotificationsCursor) time.Time { return n.LastChecked })
+}
+
+// notificationsSince calls since with the first page to determine where to stop.
+func (c *Client) notificationsSince(ctx context.Context, since func(*NotificationsCursor) time.Time) ([]Notification, error) {
+	var (
+		notifications []Notification
+		query         NotificationsQuery
+		stop          time.Time
+	)
+
+	for {
+		page, err := c.NotificationsPageContext(ctx, &query)
+		if err != nil {
+			return nil, err
+		}
+		if query.Cursor == "" {
+			stop = since(page)
+		}
+
+		for _, n := range page.Notifications {
+			if !n.SortTime.After(stop) {
+				return notifications, nil
+			}
+			notifications = append(notifications, n)
+		}
+
+		if page.Cursor == "" || len(page.Notifications) == 0 {
+			return notifications, nil
+		}
+		query.Cursor = page.Cursor
 	}
+}
 
-	err = c.checkForErrors(respBody.Errors)
+// Unread returns the notifications on this page that are newer than LastChecked.
+func (n *NotificationsCursor) Unread() []Notification {
+	return filter(n.Notifications, func(notification Notification) bool {
+		return notification.IsUnread(n.LastChecked)
+	})
+}
+
+func (n *Notification) IsUnread(lastChecked time.Time) bool {
+	return n.SortTime.After(lastChecked)
+}
+
+func (c *Client) HasNewNotes() (bool, error) {
+	return c.HasNewNotesContext(context.Background())
+}
+
+// HasNewNotesContext returns true if there are notifications that were not read yet.
+func (c *Client) HasNewNotesContext(ctx context.Context) (bool, error) {
+	query := `
+	query hasNewNotes {
+		hasNewNotes
+	}`
+
+	data, err := Do[struct {
+		HasNewNotes bool `json:"hasNewNotes"`
+	}](ctx, c, query, nil)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
-	return &respBody.Data.Notifications, nil
+	return data.HasNewNotes, nil
 }
 
-func (c *Client) Mentions() ([]Notification, error) {
-	return c.filterNotifications(
-		func(n Notification) bool {
-			return n.Type == "Mention"
-		},
-	)
+func (c *Client) MarkNotificationsRead() error {
+	return c.MarkNotificationsReadContext(context.Background())
 }
 
-func (c *Client) Replies() ([]Notification, error) {
-	return c.filterNotifications(
-		func(n Notification) bool {
-			return n.Type == "Reply"
-		},
-	)
+// MarkNotificationsReadContext marks all notifications as read.
+// SN has no mutation for this but does it when the first page of notifications is fetched.
+func (c *Client) MarkNotificationsReadContext(ctx context.Context) error {
+	_, err := c.NotificationsContext(ctx)
+	return err
 }
 
-func (c *Client) filterNotifications(f func(Notification) bool) ([]Notification, error) {
-	var (
-		n   *NotificationsCursor
-		err error
-	)
+func (n *NotificationsCursor) Mentions() []Notification {
+	return n.filter(NotificationTypeMention)
+}
+
+func (n *NotificationsCursor) Replies() []Notification {
+	return n.filter(NotificationTypeReply)
+}
+
+func (n *NotificationsCursor) filter(typename string) []Notification {
+	return filter(n.Notifications, func(n Notification) bool {
+		return n.Type == typename
+	})
+}
+
+func (c *Client) Mentions() ([]Notification, error) {
+	return c.MentionsContext(context.Background())
+}
 
-	if n, err = c.Notifications(); err != nil {
+func (c *Client) MentionsContext(ctx context.Context) ([]Notification, error) {
+	n, err := c.NotificationsContext(ctx)
+	if err != nil {
 		return nil, err
 	}
+	return n.Mentions(), nil
+}
+
+func (c *Client) Replies() ([]Notification, error) {
+	return c.RepliesContext(context.Background())
+}
 
-	return filter(n.Notifications, f), nil
+func (c *Client) RepliesContext(ctx context.Context) ([]Notification, error) {
+	n, err := c.NotificationsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return n.Replies(), nil
 }
 
 func filter[T any](s []T, f func(T) bool) []T {
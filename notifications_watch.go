@@ -0,0 +1,219 @@
+package sn
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// NotificationsCheckpoint is the position of WatchNotifications in the notification stream.
+type NotificationsCheckpoint struct {
+	// SortTime is the sort time of the newest notification that was delivered.
+	SortTime time.Time `json:"sortTime"`
+	// Seen contains the keys of the delivered notifications with this sort time.
+	Seen []string `json:"seen"`
+}
+
+// CheckpointStore persists the checkpoint of WatchNotifications.
+// Load must return nil and no error if no checkpoint was saved yet.
+type CheckpointStore interface {
+	Load() (*NotificationsCheckpoint, error)
+	Save(*NotificationsCheckpoint) error
+}
+
+// FileCheckpointStore stores the checkpoint as JSON in a file.
+type FileCheckpointStore struct {
+	Path string
+}
+
+func (s *FileCheckpointStore) Load() (*NotificationsCheckpoint, error) {
+	b, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cp NotificationsCheckpoint
+	if err = json.Unmarshal(b, &cp); err != nil {
+		err = fmt.Errorf("error decoding checkpoint %s: %w", s.Path, err)
+		return nil, err
+	}
+	return &cp, nil
+}
+
+func (s *FileCheckpointStore) Save(cp *NotificationsCheckpoint) error {
+	b, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+
+	// write to temporary file first so a crash can't leave a corrupt checkpoint behind
+	tmp := s.Path + ".tmp"
+	if err = os.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.Path)
+}
+
+type WatchNotificationsOptions struct {
+	// Interval between polls. Defaults to one minute.
+	Interval time.Duration
+	// Store persists the checkpoint and is required.
+	// Use a FileCheckpointStore to store it in a file.
+	Store CheckpointStore
+	// OnError is called with errors that happen while polling. Defaults to logging them.
+	OnError func(error)
+}
+
+// WatchNotifications polls for new notifications and sends them to the returned channel, oldest first.
+//
+// The checkpoint is saved before every notification is sent to the channel, so every
+// notification is delivered at most once: a notification that was not handled completely
+// before the process exited is not delivered again after a restart.
+// If no checkpoint exists, only notifications that arrive after the first poll are sent.
+// Since notifications are fetched from the first page, they are marked as read on SN.
+//
+// The channel is closed when the context is done.
+func (c *Client) WatchNotifications(ctx context.Context, opts *WatchNotificationsOptions) (<-chan Notification, error) {
+	var o WatchNotificationsOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Interval <= 0 {
+		o.Interval = time.Minute
+	}
+	if o.Store == nil {
+		return nil, errors.New("checkpoint store for notifications is required")
+	}
+	if o.OnError == nil {
+		o.OnError = func(err error) {
+			log.Println(err)
+		}
+	}
+
+	cp, err := o.Store.Load()
+	if err != nil {
+		return nil, err
+	}
+	if cp == nil {
+		if cp, err = c.initialCheckpoint(ctx); err != nil {
+			return nil, err
+		}
+		if err = o.Store.Save(cp); err != nil {
+			return nil, err
+		}
+	}
+
+	ch := make(chan Notification)
+	go func() {
+		defer close(ch)
+
+		ticker := time.NewTicker(o.Interval)
+		defer ticker.Stop()
+
+		for {
+			if err := c.pollNotifications(ctx, ch, cp, o.Store); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				o.OnError(err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+func (c *Client) initialCheckpoint(ctx context.Context) (*NotificationsCheckpoint, error) {
+	page, err := c.NotificationsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// use the time of the server instead of the local clock since it is compared with sort times of SN
+	if len(page.Notifications) == 0 {
+		return &NotificationsCheckpoint{SortTime: page.LastChecked}, nil
+	}
+
+	cp := &NotificationsCheckpoint{SortTime: page.Notifications[0].SortTime}
+	for _, n := range page.Notifications {
+		if n.SortTime.Equal(cp.SortTime) {
+			cp.Seen = append(cp.Seen, n.key())
+		}
+	}
+	return cp, nil
+}
+
+func (c *Client) pollNotifications(ctx context.Context, ch chan<- Notification, cp *NotificationsCheckpoint, store CheckpointStore) error {
+	// also fetch notifications with the same sort time as the checkpoint
+	// since we might not have seen all of them yet
+	notifications, err := c.NotificationsSinceContext(ctx, cp.SortTime.Add(-time.Nanosecond))
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]struct{}, len(cp.Seen))
+	for _, key := range cp.Seen {
+		seen[key] = struct{}{}
+	}
+
+	for i := len(notifications) - 1; i >= 0; i-- {
+		n := notifications[i]
+		key := n.key()
+		if _, ok := seen[key]; ok && n.SortTime.Equal(cp.SortTime) {
+			continue
+		}
+
+		// only advance the checkpoint once it was saved so the notification is retried if saving fails
+		next := NotificationsCheckpoint{SortTime: cp.SortTime, Seen: append([]string(nil), cp.Seen...)}
+		if n.SortTime.After(next.SortTime) {
+			next.SortTime = n.SortTime
+			next.Seen = nil
+		}
+		next.Seen = append(next.Seen, key)
+
+		if err = store.Save(&next); err != nil {
+			return fmt.Errorf("error saving notifications checkpoint: %w", err)
+		}
+
+		if !next.SortTime.Equal(cp.SortTime) {
+			seen = make(map[string]struct{})
+		}
+		*cp = next
+		seen[key] = struct{}{}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ch <- n:
+		}
+	}
+
+	return nil
+}
+
+// key identifies a notification. Notifications of different types can have the same id.
+// Notifications without a numeric id are identified by their raw id and sort time
+// so changes to other fields don't make them look new.
+func (n *Notification) key() string {
+	if n.Id != 0 {
+		return fmt.Sprintf("%s:%d", n.Type, n.Id)
+	}
+	var raw struct {
+		Id json.RawMessage `json:"id"`
+	}
+	_ = json.Unmarshal(n.Raw, &raw)
+	return fmt.Sprintf("%s:%s:%d", n.Type, raw.Id, n.SortTime.UnixNano())
+}
@@ -0,0 +1,40 @@
+package sn
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatchNotificationsRequiresStore(t *testing.T) {
+	c := NewClient(WithBaseUrl("http://127.0.0.1:0"))
+	if _, err := c.WatchNotifications(context.Background(), nil); err == nil {
+		t.Error("expected error without checkpoint store")
+	}
+}
+
+func TestWatchNotificationsNegativeInterval(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"notifications":{"lastChecked":"2024-01-01T00:00:00Z","notifications":[]}}}`))
+	}))
+	defer s.Close()
+	c := NewClient(WithBaseUrl(s.URL), WithApiKey("test"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	ch, err := c.WatchNotifications(ctx, &WatchNotificationsOptions{
+		Interval: -time.Second,
+		Store:    &FileCheckpointStore{Path: filepath.Join(t.TempDir(), "checkpoint.json")},
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	// the channel is closed once the context is done
+	for range ch {
+	}
+}
@@ -0,0 +1,116 @@
+package sn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// PayFunc is called with the bolt11 of an invoice that must be paid,
+// for example by handing it to your own Lightning node.
+type PayFunc func(ctx context.Context, bolt11 string) error
+
+func (c *Client) PayPaidAction(action *ItemPaidAction, pay PayFunc) (*Item, error) {
+	return c.PayPaidActionContext(context.Background(), action, pay)
+}
+
+// MaxPaidActionAttempts is the number of times PayPaidAction pays for an action
+// before it gives up if the action keeps failing.
+const MaxPaidActionAttempts = 3
+
+// PayPaidActionContext completes a pessimistic paid action.
+//
+// The invoice is paid with pay and then polled until the action was performed or failed.
+// If the action failed, SN cancels the invoice and the action is retried with retryPaidAction.
+// The invoice of the retried action is paid again, up to MaxPaidActionAttempts times.
+// Actions that are not pessimistic were already performed, so their item is returned as is.
+func (c *Client) PayPaidActionContext(ctx context.Context, action *ItemPaidAction, pay PayFunc) (*Item, error) {
+	if action == nil {
+		return nil, errors.New("paid action must not be nil")
+	}
+	if pay == nil {
+		return nil, errors.New("pay function must not be nil")
+	}
+
+	for attempt := 1; ; attempt++ {
+		if !action.IsPessimistic() {
+			return &action.Result, nil
+		}
+
+		if err := pay(ctx, action.Invoice.Bolt11); err != nil {
+			err = fmt.Errorf("error paying invoice %d: %w", action.Invoice.Id, err)
+			return nil, err
+		}
+
+		invoice, err := c.WaitForInvoiceContext(ctx, action.Invoice.Id, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		switch invoice.ActionState {
+		case ActionStatePaid:
+			return c.paidActionItem(ctx, invoice)
+		case ActionStateFailed:
+			if attempt >= MaxPaidActionAttempts {
+				return nil, fmt.Errorf("paid action of invoice %d failed %d times", invoice.Id, attempt)
+			}
+			if action, err = c.retryPaidAction(ctx, invoice.Id); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("paid action of invoice %d is in unexpected state %q", invoice.Id, invoice.ActionState)
+		}
+	}
+}
+
+// paidActionItem returns the item of the paid action that was performed.
+// The item may not be linked to the invoice yet, so the invoice is polled until it is.
+func (c *Client) paidActionItem(ctx context.Context, invoice *Invoice) (*Item, error) {
+	var (
+		interval time.Duration
+		err      error
+	)
+	for attempt := 1; invoice.Item == nil; attempt++ {
+		if attempt >= MaxPaidActionAttempts {
+			return nil, fmt.Errorf("paid action of invoice %d was performed but returned no item", invoice.Id)
+		}
+
+		interval = DefaultPollOptions.next(interval)
+		if err = sleep(ctx, interval); err != nil {
+			return nil, err
+		}
+		if invoice, err = c.InvoiceContext(ctx, invoice.Id); err != nil {
+			return nil, err
+		}
+	}
+	return invoice.Item, nil
+}
+
+func (c *Client) retryPaidAction(ctx context.Context, invoiceId int) (*ItemPaidAction, error) {
+	query := itemFields + invoiceFields + `
+	mutation retryPaidAction($invoiceId: Int!) {
+		retryPaidAction(invoiceId: $invoiceId) {
+			... on ItemPaidAction {
+				result {
+					...ItemFields
+				}
+				invoice {
+					...InvoiceFields
+				}
+				paymentMethod
+			}
+		}
+	}`
+	variables := map[string]interface{}{
+		"invoiceId": invoiceId,
+	}
+
+	data, err := Do[struct {
+		RetryPaidAction ItemPaidAction `json:"retryPaidAction"`
+	}](ctx, c, query, variables)
+	if err != nil {
+		return nil, err
+	}
+	return &data.RetryPaidAction, nil
+}
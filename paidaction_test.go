@@ -0,0 +1,116 @@
+package sn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// testPaidActionServer serves invoices with the given action states by id.
+// retryPaidAction returns a pessimistic action with the next invoice.
+func testPaidActionServer(t *testing.T, states map[int]string) *httptest.Server {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body GqlBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+			return
+		}
+
+		var data interface{}
+		switch operationName(body.Query) {
+		case "invoice":
+			id, _ := body.Variables["id"].(float64)
+			invoice := map[string]interface{}{
+				"id":          fmt.Sprint(id),
+				"actionType":  "ITEM_CREATE",
+				"actionState": states[int(id)],
+				"cancelled":   states[int(id)] == ActionStateFailed,
+			}
+			if states[int(id)] == ActionStatePaid {
+				invoice["item"] = map[string]interface{}{"id": "42"}
+			}
+			data = map[string]interface{}{"invoice": invoice}
+		case "retryPaidAction":
+			id, _ := body.Variables["invoiceId"].(float64)
+			data = map[string]interface{}{"retryPaidAction": map[string]interface{}{
+				"invoice":       map[string]interface{}{"id": fmt.Sprint(id + 1), "bolt11": fmt.Sprintf("lnbc%d", int(id)+1)},
+				"paymentMethod": PaymentMethodPessimistic,
+			}}
+		default:
+			t.Errorf("unexpected operation %s", operationName(body.Query))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func testPessimisticAction() *ItemPaidAction {
+	return &ItemPaidAction{
+		Invoice:       Invoice{Id: 1, Bolt11: "lnbc1"},
+		PaymentMethod: PaymentMethodPessimistic,
+	}
+}
+
+func TestPayPaidActionRetriesFailedAction(t *testing.T) {
+	s := testPaidActionServer(t, map[int]string{1: ActionStateFailed, 2: ActionStatePaid})
+	c := NewClient(WithBaseUrl(s.URL), WithApiKey("test"))
+
+	var paid []string
+	pay := func(ctx context.Context, bolt11 string) error {
+		paid = append(paid, bolt11)
+		return nil
+	}
+
+	item, err := c.PayPaidAction(testPessimisticAction(), pay)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if item.Id != 42 {
+		t.Errorf("expected item 42, got %d", item.Id)
+	}
+	if fmt.Sprint(paid) != "[lnbc1 lnbc2]" {
+		t.Errorf("expected invoice of retried action to be paid, got %v", paid)
+	}
+}
+
+func TestPayPaidActionGivesUp(t *testing.T) {
+	states := map[int]string{}
+	for id := 1; id <= MaxPaidActionAttempts+1; id++ {
+		states[id] = ActionStateFailed
+	}
+	s := testPaidActionServer(t, states)
+	c := NewClient(WithBaseUrl(s.URL), WithApiKey("test"))
+
+	var n int
+	pay := func(ctx context.Context, bolt11 string) error {
+		n++
+		return nil
+	}
+
+	if _, err := c.PayPaidAction(testPessimisticAction(), pay); err == nil {
+		t.Error("expected error after failed attempts")
+	}
+	if n != MaxPaidActionAttempts {
+		t.Errorf("expected %d payments, got %d", MaxPaidActionAttempts, n)
+	}
+}
+
+func TestPayPaidActionNil(t *testing.T) {
+	c := NewClient()
+	pay := func(ctx context.Context, bolt11 string) error { return nil }
+
+	if _, err := c.PayPaidAction(nil, pay); err == nil {
+		t.Error("expected error for nil action")
+	}
+	if _, err := c.PayPaidAction(testPessimisticAction(), nil); err == nil {
+		t.Error("expected error for nil pay function")
+	}
+}
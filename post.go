@@ -0,0 +1,280 @@
+package sn
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	MinBoost            = 25000
+	MinBounty           = 1000
+	MaxForwards         = 5
+	MinPollOptions      = 2
+	MaxPollOptions      = 10
+	MaxPollOptionLength = 40
+)
+
+type PostOptions struct {
+	// Boost is the amount of sats to boost the post with. Zero means no boost.
+	Boost int
+	// Forwards forward a percentage of the zaps on the post to other stackers.
+	Forwards []Forward
+}
+
+type Forward struct {
+	Nym string `json:"nym"`
+	Pct int    `json:"pct"`
+}
+
+func (o *PostOptions) validate(v *ValidationError) {
+	if o == nil {
+		return
+	}
+
+	if o.Boost != 0 && o.Boost < MinBoost {
+		v.add("boost", "must be at least %d sats", MinBoost)
+	}
+
+	if len(o.Forwards) > MaxForwards {
+		v.add("forwards", "must not be more than %d", MaxForwards)
+	}
+	var (
+		total int
+		nyms  = make(map[string]struct{})
+	)
+	for _, f := range o.Forwards {
+		if f.Nym == "" {
+			v.add("forwards", "nym must not be empty")
+		}
+		if _, ok := nyms[f.Nym]; ok {
+			v.add("forwards", "duplicate nym %s", f.Nym)
+		}
+		nyms[f.Nym] = struct{}{}
+		if f.Pct < 1 || f.Pct > 100 {
+			v.add("forwards", "percentage for %s must be between 1 and 100", f.Nym)
+		}
+		total += f.Pct
+	}
+	if total > 100 {
+		v.add("forwards", "percentages must not add up to more than 100, got %d", total)
+	}
+}
+
+func validatePollOptions(v *ValidationError, options []string) {
+	if len(options) < MinPollOptions || len(options) > MaxPollOptions {
+		v.add("options", "must have between %d and %d options", MinPollOptions, MaxPollOptions)
+	}
+	for _, o := range options {
+		if o == "" {
+			v.add("options", "option must not be empty")
+		}
+		if len([]rune(o)) > MaxPollOptionLength {
+			v.add("options", "option %q must not be longer than %d characters", o, MaxPollOptionLength)
+		}
+	}
+}
+
+// postOptions returns the options passed to a post or edit method or nil if none were passed.
+// Passing more than one is a validation error.
+func postOptions(v *ValidationError, opts []PostOptions) *PostOptions {
+	switch len(opts) {
+	case 0:
+		return nil
+	case 1:
+		return &opts[0]
+	}
+	v.add("options", "must not be passed more than once, got %d", len(opts))
+	return nil
+}
+
+func (o *PostOptions) addVariables(variables map[string]interface{}) {
+	if o == nil {
+		return
+	}
+	if o.Boost != 0 {
+		variables["boost"] = o.Boost
+	}
+	if len(o.Forwards) > 0 {
+		variables["forward"] = o.Forwards
+	}
+}
+
+func (c *Client) PostBounty(title string, text string, sub string, bounty int, opts ...PostOptions) (*ItemPaidAction, error) {
+	return c.PostBountyContext(context.Background(), title, text, sub, bounty, opts...)
+}
+
+func (c *Client) PostBountyContext(ctx context.Context, title string, text string, sub string, bounty int, opts ...PostOptions) (*ItemPaidAction, error) {
+	var v ValidationError
+	o := postOptions(&v, opts)
+	if bounty < MinBounty {
+		v.add("bounty", "must be at least %d sats", MinBounty)
+	}
+	o.validate(&v)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	query := itemFields + invoiceFields + `
+	mutation upsertBounty($title: String!, $text: String, $sub: String, $bounty: Int, $boost: Int, $forward: [ItemForwardInput]) {
+		upsertBounty(title: $title, text: $text, sub: $sub, bounty: $bounty, boost: $boost, forward: $forward) {
+			result {
+				...ItemFields
+			}
+			invoice {
+				...InvoiceFields
+			}
+			paymentMethod
+		}
+	}`
+	variables := map[string]interface{}{
+		"title":  title,
+		"text":   text,
+		"sub":    sub,
+		"bounty": bounty,
+	}
+	o.addVariables(variables)
+
+	data, err := Do[struct {
+		UpsertBounty ItemPaidAction `json:"upsertBounty"`
+	}](ctx, c, query, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.UpsertBounty, nil
+}
+
+type PostPollArgs struct {
+	Title   string
+	Text    string
+	Sub     string
+	Options []string
+	// ExpiresAt is optional. Polls without expiry never close.
+	ExpiresAt time.Time
+	// RandPollOptions shows the options in random order to every stacker.
+	RandPollOptions bool
+}
+
+func (c *Client) PostPoll(args *PostPollArgs, opts ...PostOptions) (*ItemPaidAction, error) {
+	return c.PostPollContext(context.Background(), args, opts...)
+}
+
+func (c *Client) PostPollContext(ctx context.Context, args *PostPollArgs, opts ...PostOptions) (*ItemPaidAction, error) {
+	if args == nil {
+		args = &PostPollArgs{}
+	}
+
+	var v ValidationError
+	o := postOptions(&v, opts)
+	validatePollOptions(&v, args.Options)
+	if !args.ExpiresAt.IsZero() && args.ExpiresAt.Before(time.Now()) {
+		v.add("expiresAt", "must be in the future")
+	}
+	o.validate(&v)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	query := itemFields + invoiceFields + `
+	mutation upsertPoll($title: String!, $text: String, $sub: String, $options: [String!]!, $pollExpiresAt: Date, $randPollOptions: Boolean, $boost: Int, $forward: [ItemForwardInput]) {
+		upsertPoll(title: $title, text: $text, sub: $sub, options: $options, pollExpiresAt: $pollExpiresAt, randPollOptions: $randPollOptions, boost: $boost, forward: $forward) {
+			result {
+				...ItemFields
+			}
+			invoice {
+				...InvoiceFields
+			}
+			paymentMethod
+		}
+	}`
+	variables := map[string]interface{}{
+		"title":           args.Title,
+		"text":            args.Text,
+		"sub":             args.Sub,
+		"options":         args.Options,
+		"randPollOptions": args.RandPollOptions,
+	}
+	if !args.ExpiresAt.IsZero() {
+		variables["pollExpiresAt"] = args.ExpiresAt
+	}
+	o.addVariables(variables)
+
+	data, err := Do[struct {
+		UpsertPoll ItemPaidAction `json:"upsertPoll"`
+	}](ctx, c, query, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.UpsertPoll, nil
+}
+
+type PostJobArgs struct {
+	Title    string
+	Company  string
+	Location string
+	Remote   bool
+	Text     string
+	// Url is the link or email address to apply.
+	Url string
+}
+
+func (c *Client) PostJob(args *PostJobArgs, opts ...PostOptions) (*ItemPaidAction, error) {
+	return c.PostJobContext(context.Background(), args, opts...)
+}
+
+// PostJobContext posts a job listing to the jobs territory.
+// Jobs can be boosted but zaps on them can't be forwarded.
+func (c *Client) PostJobContext(ctx context.Context, args *PostJobArgs, opts ...PostOptions) (*ItemPaidAction, error) {
+	if args == nil {
+		args = &PostJobArgs{}
+	}
+
+	var v ValidationError
+	o := postOptions(&v, opts)
+	if args.Company == "" {
+		v.add("company", "must not be empty")
+	}
+	if args.Url == "" {
+		v.add("url", "must not be empty")
+	}
+	o.validate(&v)
+	if o != nil && len(o.Forwards) > 0 {
+		v.add("forwards", "jobs don't support forwards")
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	query := itemFields + invoiceFields + `
+	mutation upsertJob($sub: String!, $title: String!, $company: String!, $location: String, $remote: Boolean, $text: String!, $url: String!, $boost: Int) {
+		upsertJob(sub: $sub, title: $title, company: $company, location: $location, remote: $remote, text: $text, url: $url, boost: $boost) {
+			result {
+				...ItemFields
+			}
+			invoice {
+				...InvoiceFields
+			}
+			paymentMethod
+		}
+	}`
+	variables := map[string]interface{}{
+		"sub":      "jobs",
+		"title":    args.Title,
+		"company":  args.Company,
+		"location": args.Location,
+		"remote":   args.Remote,
+		"text":     args.Text,
+		"url":      args.Url,
+	}
+	o.addVariables(variables)
+
+	data, err := Do[struct {
+		UpsertJob ItemPaidAction `json:"upsertJob"`
+	}](ctx, c, query, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.UpsertJob, nil
+}
@@ -0,0 +1,53 @@
+package sn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPostOptionsValidation(t *testing.T) {
+	// validation fails before any request is sent
+	c := NewClient(WithBaseUrl("http://127.0.0.1:0"))
+
+	tests := []struct {
+		name  string
+		post  func() error
+		field string
+	}{
+		{
+			name: "options passed twice",
+			post: func() error {
+				_, err := c.PostDiscussion("title", "text", "bitcoin", PostOptions{}, PostOptions{})
+				return err
+			},
+			field: "options",
+		},
+		{
+			name: "edit with too low boost",
+			post: func() error {
+				_, err := c.EditLink(1, "https://stacker.news", "title", "", "bitcoin", PostOptions{Boost: 1})
+				return err
+			},
+			field: "boost",
+		},
+		{
+			name: "job with forwards",
+			post: func() error {
+				_, err := c.PostJob(&PostJobArgs{Company: "SN", Url: "https://stacker.news"},
+					PostOptions{Forwards: []Forward{{Nym: "k00b", Pct: 10}}})
+				return err
+			},
+			field: "forwards",
+		},
+	}
+	for _, tt := range tests {
+		var v *ValidationError
+		if err := tt.post(); !errors.As(err, &v) {
+			t.Errorf("%s: expected *ValidationError, got %v", tt.name, err)
+			continue
+		}
+		if !hasFieldError(v, tt.field) {
+			t.Errorf("%s: expected error for field %s, got %v", tt.name, tt.field, v)
+		}
+	}
+}
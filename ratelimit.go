@@ -0,0 +1,87 @@
+package sn
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limiter is used to wait before every request.
+// *rate.Limiter from golang.org/x/time/rate implements this interface.
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+// RateLimiter is a token bucket that is safe for concurrent use.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter returns a token bucket that allows r requests per second
+// on average with bursts of up to burst requests.
+// Like time.NewTicker, it panics if r is not a positive finite number. A burst below 1 is raised to 1.
+func NewRateLimiter(r float64, burst int) *RateLimiter {
+	if !(r > 0) || math.IsInf(r, 1) {
+		panic(fmt.Sprintf("sn: non-positive or infinite rate %v for NewRateLimiter", r))
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   r,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+func WithRateLimit(r float64, burst int) func(*Client) {
+	return func(c *Client) {
+		c.Limiter = NewRateLimiter(r, burst)
+	}
+}
+
+func WithLimiter(limiter Limiter) func(*Client) {
+	return func(c *Client) {
+		c.Limiter = limiter
+	}
+}
+
+// Wait blocks until a token is available or the context is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	// reserve a token even if it's not available yet
+	// so concurrent callers are queued behind each other
+	l.tokens--
+	var wait time.Duration
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if wait == 0 {
+		return nil
+	}
+
+	if err := sleep(ctx, wait); err != nil {
+		// give back the reserved token
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package sn
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how failed requests are retried.
+//
+// Queries and other idempotent requests are retried on network errors and on
+// 429, 502, 503 and 504 responses. Mutations are only retried if it is known
+// that the server did not process them: if the connection could not be
+// established or if the server responded with 429.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts including the first one.
+	MaxAttempts int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+	// Jitter is the fraction of the backoff that is randomized, between 0 and 1.
+	Jitter float64
+	// OnRetry is called before waiting for the next attempt.
+	OnRetry func(RetryAttempt)
+}
+
+type RetryAttempt struct {
+	// Attempt is the number of the attempt that failed, starting at 1.
+	Attempt int
+	Err     error
+	Wait    time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	MinBackoff:  500 * time.Millisecond,
+	MaxBackoff:  30 * time.Second,
+	Jitter:      0.2,
+}
+
+func WithRetryPolicy(policy RetryPolicy) func(*Client) {
+	return func(c *Client) {
+		c.RetryPolicy = &policy
+	}
+}
+
+func (p *RetryPolicy) maxAttempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+func (p *RetryPolicy) backoff(attempt int, err error) time.Duration {
+	wait := time.Duration(float64(p.MinBackoff) * math.Pow(2, float64(attempt-1)))
+	if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		wait -= time.Duration(rand.Float64() * p.Jitter * float64(wait))
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		if retryAfter, ok := parseRetryAfter(httpErr.Header.Get("Retry-After")); ok && retryAfter > wait {
+			wait = retryAfter
+		}
+	}
+
+	return wait
+}
+
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return time.Until(t), true
+	}
+	return 0, false
+}
+
+func isRetryable(err error, idempotent bool) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		switch httpErr.StatusCode {
+		case http.StatusTooManyRequests:
+			return true
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return idempotent
+		}
+		return false
+	}
+
+	var graphqlErr *GraphQLError
+	if errors.As(err, &graphqlErr) {
+		return false
+	}
+
+	if idempotent {
+		return true
+	}
+
+	// the request was never sent if we could not connect
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
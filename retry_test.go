@@ -0,0 +1,196 @@
+package sn
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testRetryServer responds with the given status codes in order and with 200 afterwards.
+func testRetryServer(t *testing.T, header http.Header, statusCodes ...int) (*httptest.Server, *int32) {
+	var requests int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&requests, 1))
+		if n <= len(statusCodes) {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(statusCodes[n-1])
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"ok":true}}`))
+	}))
+	t.Cleanup(s.Close)
+	return s, &requests
+}
+
+func testRetryClient(url string, policy RetryPolicy) *Client {
+	return NewClient(WithBaseUrl(url), WithApiKey("test"), WithRetryPolicy(policy))
+}
+
+type okData struct {
+	Ok bool `json:"ok"`
+}
+
+func TestRetryQueryOnBadGateway(t *testing.T) {
+	s, requests := testRetryServer(t, nil, http.StatusBadGateway)
+	c := testRetryClient(s.URL, RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond})
+
+	data, err := Do[okData](context.Background(), c, `query ok { ok }`, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !data.Ok {
+		t.Error("query did not return data")
+	}
+	if n := atomic.LoadInt32(requests); n != 2 {
+		t.Errorf("expected 2 requests, got %d", n)
+	}
+}
+
+func TestRetryMutationOnBadGateway(t *testing.T) {
+	s, requests := testRetryServer(t, nil, http.StatusBadGateway)
+	c := testRetryClient(s.URL, RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond})
+
+	_, err := Do[okData](context.Background(), c, `mutation ok { ok }`, nil)
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusBadGateway {
+		t.Errorf("expected HTTPError with status 502, got %v", err)
+	}
+	if n := atomic.LoadInt32(requests); n != 1 {
+		t.Errorf("mutation must not be retried after 502, got %d requests", n)
+	}
+}
+
+func TestRetryMutationOnDialError(t *testing.T) {
+	// reserve a port and close it again so nothing is listening on it
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	var attempts []RetryAttempt
+	c := testRetryClient("http://"+addr, RetryPolicy{
+		MaxAttempts: 2,
+		MinBackoff:  time.Millisecond,
+		OnRetry:     func(a RetryAttempt) { attempts = append(attempts, a) },
+	})
+
+	if _, err = Do[okData](context.Background(), c, `mutation ok { ok }`, nil); err == nil {
+		t.Error("expected dial error")
+	}
+	if len(attempts) != 1 {
+		t.Errorf("mutation should be retried once after dial error, got %d retries", len(attempts))
+	}
+}
+
+func TestRetryTooManyRequestsWithRetryAfter(t *testing.T) {
+	header := http.Header{"Retry-After": []string{"1"}}
+	s, requests := testRetryServer(t, header, http.StatusTooManyRequests)
+
+	var attempts []RetryAttempt
+	c := testRetryClient(s.URL, RetryPolicy{
+		MaxAttempts: 2,
+		MinBackoff:  time.Millisecond,
+		OnRetry:     func(a RetryAttempt) { attempts = append(attempts, a) },
+	})
+
+	if _, err := Do[okData](context.Background(), c, `mutation ok { ok }`, nil); err != nil {
+		t.Error(err)
+		return
+	}
+	if n := atomic.LoadInt32(requests); n != 2 {
+		t.Errorf("expected 2 requests, got %d", n)
+	}
+	if len(attempts) != 1 {
+		t.Errorf("expected 1 retry, got %d", len(attempts))
+		return
+	}
+	if attempts[0].Wait != time.Second {
+		t.Errorf("expected to wait 1s as requested by Retry-After, got %s", attempts[0].Wait)
+	}
+	if !errors.Is(attempts[0].Err, ErrRateLimited) {
+		t.Errorf("expected ErrRateLimited, got %v", attempts[0].Err)
+	}
+}
+
+func TestRetryContextCanceledDuringBackoff(t *testing.T) {
+	s, requests := testRetryServer(t, nil, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := testRetryClient(s.URL, RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  time.Hour,
+		OnRetry:     func(RetryAttempt) { cancel() },
+	})
+
+	start := time.Now()
+	_, err := Do[okData](ctx, c, `query ok { ok }`, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("backoff was not interrupted by context cancellation")
+	}
+	if n := atomic.LoadInt32(requests); n != 1 {
+		t.Errorf("expected 1 request, got %d", n)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{"soon", 0, false},
+		{time.Now().Add(time.Minute).UTC().Format(http.TimeFormat), time.Minute, true},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value)
+		if ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q): expected ok=%v, got %v", tt.value, tt.ok, ok)
+			continue
+		}
+		// HTTP dates only have a precision of seconds
+		if d := got - tt.want; d > time.Second || d < -time.Second {
+			t.Errorf("parseRetryAfter(%q): expected %s, got %s", tt.value, tt.want, got)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	p := RetryPolicy{MinBackoff: time.Second, MaxBackoff: 5 * time.Second, Jitter: 0.5}
+
+	tests := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 5 * time.Second},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			wait := p.backoff(tt.attempt, errors.New("network error"))
+			if wait > tt.max || wait < tt.max/2 {
+				t.Errorf("attempt %d: expected backoff between %s and %s, got %s", tt.attempt, tt.max/2, tt.max, wait)
+				break
+			}
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package sn
 
 import (
+	"context"
 	"encoding/xml"
 	"fmt"
 	"log"
@@ -50,8 +51,21 @@ func (c *RssDate) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 }
 
 func (c *Client) GetRssFeed() (*Rss, error) {
+	return c.GetRssFeedContext(context.Background())
+}
+
+func (c *Client) GetRssFeedContext(ctx context.Context) (*Rss, error) {
 	url := fmt.Sprintf("%s/rss", c.BaseUrl)
-	resp, err := http.Get(url)
+	newRequest := func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			err = fmt.Errorf("error preparing RSS request: %w", err)
+			return nil, err
+		}
+		return req, nil
+	}
+
+	resp, err := c.do(ctx, newRequest, true)
 	if err != nil {
 		err = fmt.Errorf("error fetching RSS feed: %w", err)
 		log.Println(err)
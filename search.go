@@ -0,0 +1,119 @@
+package sn
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type SearchQuery struct {
+	// Q is the search query. SearchTerms can be used to build queries with operators.
+	Q      string
+	Sub    string
+	Sort   string
+	What   string
+	When   string
+	From   time.Time
+	To     time.Time
+	Cursor string
+}
+
+// SearchTerms builds search queries with the operators that SN supports.
+//
+//	q := sn.NewSearchTerms("lightning").Exact("channel jamming").Nym("k00b").String()
+type SearchTerms struct {
+	terms []string
+}
+
+func NewSearchTerms(terms ...string) *SearchTerms {
+	return &SearchTerms{terms: terms}
+}
+
+func (s *SearchTerms) Term(term string) *SearchTerms {
+	s.terms = append(s.terms, term)
+	return s
+}
+
+// Exact only matches items that contain the phrase as is.
+// Quotes inside the phrase are removed since SN does not support escaping them.
+func (s *SearchTerms) Exact(phrase string) *SearchTerms {
+	phrase = strings.Join(strings.Fields(strings.ReplaceAll(phrase, `"`, " ")), " ")
+	if phrase != "" {
+		s.terms = append(s.terms, `"`+phrase+`"`)
+	}
+	return s
+}
+
+// Nym only matches items of the given stacker.
+// Whitespace is removed since it would split the operator into separate terms.
+func (s *SearchTerms) Nym(name string) *SearchTerms {
+	return s.operator("nym", name)
+}
+
+// Url only matches links to the given URL or domain.
+// Whitespace is removed since it would split the operator into separate terms.
+func (s *SearchTerms) Url(url string) *SearchTerms {
+	return s.operator("url", url)
+}
+
+// operator adds the operator with the value unless the value is empty.
+func (s *SearchTerms) operator(name string, value string) *SearchTerms {
+	value = strings.Join(strings.Fields(value), "")
+	if value != "" {
+		s.terms = append(s.terms, fmt.Sprintf("%s:%s", name, value))
+	}
+	return s
+}
+
+func (s *SearchTerms) String() string {
+	return strings.Join(s.terms, " ")
+}
+
+func (c *Client) Search(query *SearchQuery) (*ItemsCursor, error) {
+	return c.SearchContext(context.Background(), query)
+}
+
+// SearchContext returns a page of items matching the query.
+// Matches are highlighted in SearchTitle and SearchText of the items.
+func (c *Client) SearchContext(ctx context.Context, q *SearchQuery) (*ItemsCursor, error) {
+	if q == nil {
+		q = &SearchQuery{}
+	}
+
+	query := itemFields + `
+	query search($q: String, $sub: String, $cursor: String, $what: String, $sort: String, $when: String, $from: String, $to: String) {
+		search(q: $q, sub: $sub, cursor: $cursor, what: $what, sort: $sort, when: $when, from: $from, to: $to) {
+			cursor
+			items {
+				...ItemFields
+				searchTitle
+				searchText
+			}
+		}
+	}`
+	variables := map[string]interface{}{
+		"q":      q.Q,
+		"sub":    q.Sub,
+		"cursor": q.Cursor,
+		"what":   q.What,
+		"sort":   q.Sort,
+		"when":   q.When,
+	}
+	// from and to are timestamps in milliseconds
+	if !q.From.IsZero() {
+		variables["from"] = strconv.FormatInt(q.From.UnixMilli(), 10)
+	}
+	if !q.To.IsZero() {
+		variables["to"] = strconv.FormatInt(q.To.UnixMilli(), 10)
+	}
+
+	data, err := Do[struct {
+		Search ItemsCursor `json:"search"`
+	}](ctx, c, query, variables)
+	if err != nil {
+		return nil, err
+	}
+	return &data.Search, nil
+}
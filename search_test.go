@@ -0,0 +1,23 @@
+package sn
+
+import "testing"
+
+func TestSearchTerms(t *testing.T) {
+	tests := []struct {
+		terms *SearchTerms
+		want  string
+	}{
+		{NewSearchTerms("lightning").Exact("channel jamming"), `lightning "channel jamming"`},
+		{NewSearchTerms().Exact(`say "hi"` + "\tthere"), `"say hi there"`},
+		{NewSearchTerms().Exact(` " `), ``},
+		{NewSearchTerms().Nym(" k00b "), `nym:k00b`},
+		{NewSearchTerms().Nym("k0 0b"), `nym:k00b`},
+		{NewSearchTerms().Url("stacker.news "), `url:stacker.news`},
+		{NewSearchTerms("a").Nym(" ").Url(""), `a`},
+	}
+	for _, tt := range tests {
+		if got := tt.terms.String(); got != tt.want {
+			t.Errorf("expected %q, got %q", tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,295 @@
+package sn
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+// Sub is a territory.
+type Sub struct {
+	Name             string      `json:"name"`
+	CreatedAt        time.Time   `json:"createdAt"`
+	UserId           int         `json:"userId"`
+	User             User        `json:"user"`
+	Desc             string      `json:"desc"`
+	Status           SubStatus   `json:"status"`
+	PostTypes        []PostType  `json:"postTypes"`
+	AllowFreebies    bool        `json:"allowFreebies"`
+	RankingType      string      `json:"rankingType"`
+	BillingType      BillingType `json:"billingType"`
+	BillingCost      int         `json:"billingCost"`
+	BillingAutoRenew bool        `json:"billingAutoRenew"`
+	BilledLastAt     time.Time   `json:"billedLastAt"`
+	BillPaidUntil    null.Time   `json:"billPaidUntil"`
+	BaseCost         int         `json:"baseCost"`
+	ReplyCost        int         `json:"replyCost"`
+	Moderated        bool        `json:"moderated"`
+	ModeratedCount   int         `json:"moderatedCount"`
+	MeMuteSub        bool        `json:"meMuteSub"`
+	Nsfw             bool        `json:"nsfw"`
+	NPosts           int         `json:"nposts"`
+	NComments        int         `json:"ncomments"`
+}
+
+type SubStatus string
+
+const (
+	SubStatusActive  SubStatus = "ACTIVE"
+	SubStatusGrace   SubStatus = "GRACE"
+	SubStatusStopped SubStatus = "STOPPED"
+)
+
+type PostType string
+
+const (
+	PostTypeLink       PostType = "LINK"
+	PostTypeDiscussion PostType = "DISCUSSION"
+	PostTypePoll       PostType = "POLL"
+	PostTypeBounty     PostType = "BOUNTY"
+	PostTypeJob        PostType = "JOB"
+)
+
+type BillingType string
+
+const (
+	BillingTypeMonthly BillingType = "MONTHLY"
+	BillingTypeYearly  BillingType = "YEARLY"
+	BillingTypeOnce    BillingType = "ONCE"
+)
+
+func (s *Sub) AllowsPostType(t PostType) bool {
+	for _, postType := range s.PostTypes {
+		if postType == t {
+			return true
+		}
+	}
+	return false
+}
+
+type SubPaidAction struct {
+	Result        Sub           `json:"result"`
+	Invoice       Invoice       `json:"invoice"`
+	PaymentMethod PaymentMethod `json:"paymentMethod"`
+}
+
+const subFields = `
+fragment SubFields on Sub {
+	name
+	createdAt
+	userId
+	user {
+		id
+		name
+	}
+	desc
+	status
+	postTypes
+	allowFreebies
+	rankingType
+	billingType
+	billingCost
+	billingAutoRenew
+	billedLastAt
+	billPaidUntil
+	baseCost
+	replyCost
+	moderated
+	moderatedCount
+	meMuteSub
+	nsfw
+	nposts
+	ncomments
+}`
+
+func (c *Client) Subs() ([]Sub, error) {
+	return c.SubsContext(context.Background())
+}
+
+func (c *Client) SubsContext(ctx context.Context) ([]Sub, error) {
+	query := subFields + `
+	query subs {
+		subs {
+			...SubFields
+		}
+	}`
+
+	data, err := Do[struct {
+		Subs []Sub `json:"subs"`
+	}](ctx, c, query, nil)
+	if err != nil {
+		return nil, err
+	}
+	return data.Subs, nil
+}
+
+func (c *Client) Sub(name string) (*Sub, error) {
+	return c.SubContext(context.Background(), name)
+}
+
+func (c *Client) SubContext(ctx context.Context, name string) (*Sub, error) {
+	query := subFields + `
+	query sub($name: String!) {
+		sub(name: $name) {
+			...SubFields
+		}
+	}`
+	variables := map[string]interface{}{
+		"name": name,
+	}
+
+	data, err := Do[struct {
+		Sub *Sub `json:"sub"`
+	}](ctx, c, query, variables)
+	if err != nil {
+		return nil, err
+	}
+	if data.Sub == nil {
+		return nil, fmt.Errorf("territory %s: %w", name, ErrNotFound)
+	}
+	return data.Sub, nil
+}
+
+type UpsertSubArgs struct {
+	// OldName must be set to the current name when editing a territory.
+	OldName          string
+	Name             string
+	Desc             string
+	BaseCost         int
+	ReplyCost        int
+	PostTypes        []PostType
+	AllowFreebies    bool
+	BillingType      BillingType
+	BillingAutoRenew bool
+	Moderated        bool
+	Nsfw             bool
+}
+
+func (c *Client) UpsertSub(args *UpsertSubArgs) (*SubPaidAction, error) {
+	return c.UpsertSubContext(context.Background(), args)
+}
+
+// UpsertSubContext creates a territory or edits it if OldName is set.
+func (c *Client) UpsertSubContext(ctx context.Context, args *UpsertSubArgs) (*SubPaidAction, error) {
+	if args == nil {
+		args = &UpsertSubArgs{}
+	}
+
+	query := subFields + invoiceFields + `
+	mutation upsertSub($oldName: String, $name: String!, $desc: String, $baseCost: Int!, $replyCost: Int!, $postTypes: [String!]!, $allowFreebies: Boolean!, $billingType: String!, $billingAutoRenew: Boolean!, $moderated: Boolean!, $nsfw: Boolean!) {
+		upsertSub(oldName: $oldName, name: $name, desc: $desc, baseCost: $baseCost, replyCost: $replyCost, postTypes: $postTypes, allowFreebies: $allowFreebies, billingType: $billingType, billingAutoRenew: $billingAutoRenew, moderated: $moderated, nsfw: $nsfw) {
+			result {
+				...SubFields
+			}
+			invoice {
+				...InvoiceFields
+			}
+			paymentMethod
+		}
+	}`
+	variables := map[string]interface{}{
+		"name":             args.Name,
+		"desc":             args.Desc,
+		"baseCost":         args.BaseCost,
+		"replyCost":        args.ReplyCost,
+		"postTypes":        args.PostTypes,
+		"allowFreebies":    args.AllowFreebies,
+		"billingType":      args.BillingType,
+		"billingAutoRenew": args.BillingAutoRenew,
+		"moderated":        args.Moderated,
+		"nsfw":             args.Nsfw,
+	}
+	if args.OldName != "" {
+		variables["oldName"] = args.OldName
+	}
+
+	data, err := Do[struct {
+		UpsertSub SubPaidAction `json:"upsertSub"`
+	}](ctx, c, query, variables)
+	if err != nil {
+		return nil, err
+	}
+	return &data.UpsertSub, nil
+}
+
+func (c *Client) PaySub(name string) (*SubPaidAction, error) {
+	return c.PaySubContext(context.Background(), name)
+}
+
+// PaySubContext pays the bill of the territory.
+func (c *Client) PaySubContext(ctx context.Context, name string) (*SubPaidAction, error) {
+	query := subFields + invoiceFields + `
+	mutation paySub($name: String!) {
+		paySub(name: $name) {
+			result {
+				...SubFields
+			}
+			invoice {
+				...InvoiceFields
+			}
+			paymentMethod
+		}
+	}`
+	variables := map[string]interface{}{
+		"name": name,
+	}
+
+	data, err := Do[struct {
+		PaySub SubPaidAction `json:"paySub"`
+	}](ctx, c, query, variables)
+	if err != nil {
+		return nil, err
+	}
+	return &data.PaySub, nil
+}
+
+func (c *Client) ToggleMuteSub(name string) (bool, error) {
+	return c.ToggleMuteSubContext(context.Background(), name)
+}
+
+// ToggleMuteSubContext mutes or unmutes the territory and returns if it is muted now.
+func (c *Client) ToggleMuteSubContext(ctx context.Context, name string) (bool, error) {
+	query := `
+	mutation toggleMuteSub($name: String!) {
+		toggleMuteSub(name: $name)
+	}`
+	variables := map[string]interface{}{
+		"name": name,
+	}
+
+	data, err := Do[struct {
+		ToggleMuteSub bool `json:"toggleMuteSub"`
+	}](ctx, c, query, variables)
+	if err != nil {
+		return false, err
+	}
+	return data.ToggleMuteSub, nil
+}
+
+func (c *Client) TransferTerritory(subName string, userName string) (*Sub, error) {
+	return c.TransferTerritoryContext(context.Background(), subName, userName)
+}
+
+// TransferTerritoryContext transfers ownership of the territory to another stacker.
+func (c *Client) TransferTerritoryContext(ctx context.Context, subName string, userName string) (*Sub, error) {
+	query := subFields + `
+	mutation transferTerritory($subName: String!, $userName: String!) {
+		transferTerritory(subName: $subName, userName: $userName) {
+			...SubFields
+		}
+	}`
+	variables := map[string]interface{}{
+		"subName":  subName,
+		"userName": userName,
+	}
+
+	data, err := Do[struct {
+		TransferTerritory Sub `json:"transferTerritory"`
+	}](ctx, c, query, variables)
+	if err != nil {
+		return nil, err
+	}
+	return &data.TransferTerritory, nil
+}
@@ -2,7 +2,7 @@ package sn
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
 	"image"
 	"image/png"
@@ -16,14 +16,17 @@ type GetSignedPOST struct {
 	Fields map[string]string `json:"fields"`
 }
 
-type GetSignedPOSTResponse struct {
-	Errors []GqlError `json:"errors"`
-	Data   struct {
-		GetSignedPOST GetSignedPOST `json:"getSignedPOST"`
-	} `json:"data"`
+type getSignedPOSTData struct {
+	GetSignedPOST GetSignedPOST `json:"getSignedPOST"`
 }
 
+type GetSignedPOSTResponse = GqlResponse[getSignedPOSTData]
+
 func (c *Client) UploadImage(img *image.RGBA) (string, error) {
+	return c.UploadImageContext(context.Background(), img)
+}
+
+func (c *Client) UploadImageContext(ctx context.Context, img *image.RGBA) (string, error) {
 	var (
 		b      = img.Bounds()
 		width  = b.Dx()
@@ -39,43 +42,28 @@ func (c *Client) UploadImage(img *image.RGBA) (string, error) {
 	size = imgBuf.Len()
 
 	// get signed URL for S3 upload
-	body := GqlBody{
-		Query: `
-		mutation getSignedPOST($type: String!, $size: Int!, $width: Int!, $height: Int!, $avatar: Boolean) {
-			getSignedPOST(type: $type, size: $size, width: $width, height: $height, avatar: $avatar) {
-				url
-				fields
-			}
-		}`,
-		Variables: map[string]interface{}{
-			"type":   type_,
-			"size":   size,
-			"width":  width,
-			"height": height,
-			"avatar": false,
-		},
-	}
-
-	resp, err := c.callApi(body)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	var respBody GetSignedPOSTResponse
-	err = json.NewDecoder(resp.Body).Decode(&respBody)
-	if err != nil {
-		err = fmt.Errorf("error decoding getSignedPOST: %w", err)
-		return "", err
+	query := `
+	mutation getSignedPOST($type: String!, $size: Int!, $width: Int!, $height: Int!, $avatar: Boolean) {
+		getSignedPOST(type: $type, size: $size, width: $width, height: $height, avatar: $avatar) {
+			url
+			fields
+		}
+	}`
+	variables := map[string]interface{}{
+		"type":   type_,
+		"size":   size,
+		"width":  width,
+		"height": height,
+		"avatar": false,
 	}
 
-	err = c.checkForErrors(respBody.Errors)
+	data, err := Do[getSignedPOSTData](ctx, c, query, variables)
 	if err != nil {
 		return "", err
 	}
 
-	s3Url := respBody.Data.GetSignedPOST.Url
-	fields := respBody.Data.GetSignedPOST.Fields
+	s3Url := data.GetSignedPOST.Url
+	fields := data.GetSignedPOST.Fields
 
 	// create multipart form
 	var (
@@ -112,19 +100,22 @@ func (c *Client) UploadImage(img *image.RGBA) (string, error) {
 	}
 
 	// upload to S3
-	var req *http.Request
-	if req, err = http.NewRequest("POST", s3Url, &buf); err != nil {
-		return "", err
+	newRequest := func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", s3Url, bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		return req, nil
 	}
-	req.Header.Set("Content-Type", w.FormDataContentType())
 
-	client := http.DefaultClient
-	if resp, err = client.Do(req); err != nil {
+	resp, err := c.do(ctx, newRequest, false)
+	if err != nil {
 		return "", err
 	}
 	defer resp.Body.Close()
 
-	imgId := respBody.Data.GetSignedPOST.Fields["key"]
+	imgId := data.GetSignedPOST.Fields["key"]
 	imgUrl := fmt.Sprintf("%s/%s", c.MediaUrl, imgId)
 
 	return imgUrl, nil
@@ -1,57 +1,235 @@
 package sn
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/guregu/null.v4"
 )
 
 type User struct {
-	Id       int          `json:"id,string"`
-	Name     string       `json:"name"`
-	Privates UserPrivates `json:"privates"`
+	Id        int       `json:"id,string"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Since is the id of the first item of the user.
+	Since     int          `json:"since"`
+	PhotoId   int          `json:"photoId"`
+	Bio       *Item        `json:"bio"`
+	NItems    int          `json:"nitems"`
+	NPosts    int          `json:"nposts"`
+	NComments int          `json:"ncomments"`
+	Optional  UserOptional `json:"optional"`
+	Privates  UserPrivates `json:"privates"`
+}
+
+// UserOptional contains the stats that users can choose to hide.
+// Hidden stats are null. Streak is also null if the user hides the cowboy hat.
+type UserOptional struct {
+	Stacked       null.Int  `json:"stacked"`
+	Spent         null.Int  `json:"spent"`
+	Referrals     null.Int  `json:"referrals"`
+	Streak        null.Int  `json:"streak"`
+	MaxStreak     null.Int  `json:"maxStreak"`
+	IsContributor null.Bool `json:"isContributor"`
+	HasSendWallet null.Bool `json:"hasSendWallet"`
+	HasRecvWallet null.Bool `json:"hasRecvWallet"`
+}
+
+// MeFields selects which groups of fields Me fetches in addition to id, name and sats.
+//
+// Some account state is not exposed on the user by SN:
+//   - rewards are only reported with Earn notifications
+//   - there are no counts of muted users or subscriptions
+//   - when notifications were last checked is returned as NotificationsCursor.LastChecked
+//   - referrals are credited by name so there is no referral code, see ReferralUrl
+type MeFields int
+
+const (
+	// MeFieldsWallet selects fee credits and if wallets are attached.
+	MeFieldsWallet MeFields = 1 << iota
+	// MeFieldsStats selects streak, stacked, spent and referrals.
+	MeFieldsStats
+	// MeFieldsSettings selects account settings like the default tip amount.
+	MeFieldsSettings
+	// MeFieldsNotificationSettings selects which notifications are enabled.
+	MeFieldsNotificationSettings
+	// MeFieldsNostr selects the nostr pubkey and relays of the user.
+	MeFieldsNostr
+
+	MeFieldsAll = MeFieldsWallet | MeFieldsStats | MeFieldsSettings | MeFieldsNotificationSettings | MeFieldsNostr
+)
+
+func (f MeFields) selection() string {
+	var (
+		privates = []string{"sats"}
+		optional []string
+	)
+
+	if f&MeFieldsWallet != 0 {
+		privates = append(privates, "credits")
+		optional = append(optional, "hasSendWallet", "hasRecvWallet")
+	}
+	if f&MeFieldsStats != 0 {
+		optional = append(optional, "stacked", "spent", "referrals", "streak", "maxStreak", "isContributor")
+	}
+	if f&MeFieldsSettings != 0 {
+		privates = append(privates,
+			"tipDefault", "turboTipping", "zapUndos", "fiatCurrency", "withdrawMaxFeeDefault", "autoDropBolt11s",
+			"hideCowboyHat", "hideFromTopUsers", "hideWalletBalance", "imgproxyOnly", "wildWestMode", "satsFilter",
+			"hasInvites", "lastCheckedJobs")
+	}
+	if f&MeFieldsNotificationSettings != 0 {
+		privates = append(privates,
+			"noteItemSats", "noteEarning", "noteAllDescendants", "noteMentions", "noteItemMentions", "noteDeposits",
+			"noteWithdrawals", "noteInvites", "noteJobIndicator", "noteCowboyHat", "noteForwardedSats")
+	}
+	if f&MeFieldsNostr != 0 {
+		privates = append(privates, "nostrPubkey", "nostrRelays", "nostrCrossposting")
+	}
+
+	selection := fmt.Sprintf("privates { %s }", strings.Join(privates, " "))
+	if len(optional) > 0 {
+		selection += fmt.Sprintf(" optional { %s }", strings.Join(optional, " "))
+	}
+	return selection
 }
 
 type UserPrivates struct {
-	Sats int `json:"sats"`
+	Sats                  int      `json:"sats"`
+	Credits               int      `json:"credits"`
+	TipDefault            int      `json:"tipDefault"`
+	TurboTipping          bool     `json:"turboTipping"`
+	ZapUndos              null.Int `json:"zapUndos"`
+	FiatCurrency          string   `json:"fiatCurrency"`
+	WithdrawMaxFeeDefault int      `json:"withdrawMaxFeeDefault"`
+	AutoDropBolt11s       bool     `json:"autoDropBolt11s"`
+	HideCowboyHat         bool     `json:"hideCowboyHat"`
+	HideFromTopUsers      bool     `json:"hideFromTopUsers"`
+	HideWalletBalance     bool     `json:"hideWalletBalance"`
+	ImgproxyOnly          bool     `json:"imgproxyOnly"`
+	WildWestMode          bool     `json:"wildWestMode"`
+	SatsFilter            int      `json:"satsFilter"`
+	HasInvites            bool     `json:"hasInvites"`
+	// LastCheckedJobs is when the user last looked at the jobs territory.
+	// It is not related to notifications.
+	LastCheckedJobs null.Time `json:"lastCheckedJobs"`
+
+	NostrPubkey       null.String `json:"nostrPubkey"`
+	NostrRelays       []string    `json:"nostrRelays"`
+	NostrCrossposting bool        `json:"nostrCrossposting"`
+
+	NoteItemSats       bool `json:"noteItemSats"`
+	NoteEarning        bool `json:"noteEarning"`
+	NoteAllDescendants bool `json:"noteAllDescendants"`
+	NoteMentions       bool `json:"noteMentions"`
+	NoteItemMentions   bool `json:"noteItemMentions"`
+	NoteDeposits       bool `json:"noteDeposits"`
+	NoteWithdrawals    bool `json:"noteWithdrawals"`
+	NoteInvites        bool `json:"noteInvites"`
+	NoteJobIndicator   bool `json:"noteJobIndicator"`
+	NoteCowboyHat      bool `json:"noteCowboyHat"`
+	NoteForwardedSats  bool `json:"noteForwardedSats"`
 }
 
-type MeResponse struct {
-	Errors []GqlError `json:"errors"`
-	Data   struct {
-		Me User `json:"me"`
-	} `json:"data"`
+type meData struct {
+	Me User `json:"me"`
 }
 
-func (c *Client) Me() (*User, error) {
-	body := GqlBody{
-		Query: `
-		query me {
-			me {
-				id
-				name
-				privates {
-					sats
-				}
-			}
-		}`,
+type MeResponse = GqlResponse[meData]
+
+func (c *Client) Me(fields ...MeFields) (*User, error) {
+	return c.MeContext(context.Background(), fields...)
+}
+
+// MeContext fetches the authenticated user.
+// Without fields, only id, name and sats are fetched to keep the call cheap.
+func (c *Client) MeContext(ctx context.Context, fields ...MeFields) (*User, error) {
+	var f MeFields
+	for _, field := range fields {
+		f |= field
 	}
 
-	resp, err := c.callApi(body)
+	query := `
+	query me {
+		me {
+			id
+			name
+			createdAt
+			` + f.selection() + `
+		}
+	}`
+
+	data, err := Do[meData](ctx, c, query, nil)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
+	return &data.Me, nil
+}
 
-	var respBody MeResponse
-	err = json.NewDecoder(resp.Body).Decode(&respBody)
-	if err != nil {
-		err = fmt.Errorf("error decoding me: %w", err)
-		return nil, err
+func (c *Client) User(name string) (*User, error) {
+	return c.UserContext(context.Background(), name)
+}
+
+// UserContext fetches the public profile of the user.
+// SN only exposes hideCowboyHat to the user itself, see MeFieldsSettings.
+// For other users, a hidden cowboy hat shows as a null Optional.Streak.
+func (c *Client) UserContext(ctx context.Context, name string) (*User, error) {
+	query := `
+	query user($name: String!) {
+		user(name: $name) {
+			id
+			name
+			createdAt
+			since
+			photoId
+			nitems
+			nposts
+			ncomments
+			bio {
+				id
+				text
+				createdAt
+			}
+			optional {
+				stacked
+				spent
+				referrals
+				streak
+				maxStreak
+				isContributor
+			}
+		}
+	}`
+	variables := map[string]interface{}{
+		"name": name,
 	}
 
-	err = c.checkForErrors(respBody.Errors)
+	data, err := Do[struct {
+		User *User `json:"user"`
+	}](ctx, c, query, variables)
 	if err != nil {
 		return nil, err
 	}
-	return &respBody.Data.Me, nil
+	if data.User == nil {
+		return nil, fmt.Errorf("user %s: %w", name, ErrNotFound)
+	}
+	return data.User, nil
+}
+
+func (c *Client) UserItems(name string, type_ string) (*ItemsCursor, error) {
+	return c.UserItemsContext(context.Background(), name, type_)
+}
+
+// UserItemsContext returns the first page of items of the user.
+// type_ can be used to only return posts or comments for example.
+// Use ItemsIter with the same query to fetch all pages.
+func (c *Client) UserItemsContext(ctx context.Context, name string, type_ string) (*ItemsCursor, error) {
+	return c.ItemsContext(ctx, &ItemsQuery{Sort: "user", Name: name, Type: type_})
+}
+
+// ReferralUrl returns the link that credits referrals to the user.
+func (c *Client) ReferralUrl(u *User) string {
+	return fmt.Sprintf("%s/r/%s", c.BaseUrl, u.Name)
 }
@@ -0,0 +1,218 @@
+package sn
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	MaxTitleLength    = 80
+	MaxPostTextLength = 100000
+)
+
+// FieldError describes why the value of a field is invalid.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e FieldError) String() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationError is returned if input was rejected before it was sent to SN.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	var msgs []string
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.String())
+	}
+	return fmt.Sprintf("validation failed: %s", strings.Join(msgs, "; "))
+}
+
+func (e *ValidationError) add(field string, format string, args ...interface{}) {
+	e.Errors = append(e.Errors, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// err returns nil if no field errors were added so it can be returned as an error directly.
+func (e *ValidationError) err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Draft is a post that was not sent yet.
+type Draft struct {
+	Type    PostType
+	Title   string
+	Text    string
+	Url     string
+	Bounty  int
+	Options []string
+	PostOptions
+}
+
+// PostCost is the estimated cost of a post in sats.
+type PostCost struct {
+	BaseCost int
+	// Multiplier is 10 to the power of the number of posts created within the last 10 minutes.
+	Multiplier int
+	Boost      int
+	Total      int
+}
+
+// ValidateDraft checks the draft against the rules of the territory and estimates its cost.
+// recentPosts is the number of posts created by the user within the last 10 minutes.
+// The estimated cost is returned even if the draft is invalid unless sub or draft is nil.
+func ValidateDraft(sub *Sub, draft *Draft, recentPosts int) (*PostCost, error) {
+	var v ValidationError
+	if sub == nil {
+		v.add("sub", "must not be nil")
+	}
+	if draft == nil {
+		v.add("draft", "must not be nil")
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	if sub.Status == SubStatusStopped {
+		v.add("sub", "territory %s is stopped", sub.Name)
+	}
+	if !sub.AllowsPostType(draft.Type) {
+		v.add("type", "territory %s does not allow %s posts", sub.Name, strings.ToLower(string(draft.Type)))
+	}
+
+	title := strings.TrimSpace(draft.Title)
+	if title == "" {
+		v.add("title", "must not be empty")
+	}
+	if n := len([]rune(title)); n > MaxTitleLength {
+		v.add("title", "must not be longer than %d characters, got %d", MaxTitleLength, n)
+	}
+	if n := len([]rune(draft.Text)); n > MaxPostTextLength {
+		v.add("text", "must not be longer than %d characters, got %d", MaxPostTextLength, n)
+	}
+
+	switch draft.Type {
+	case PostTypeLink:
+		validateUrl(&v, draft.Url)
+	case PostTypeBounty:
+		if draft.Bounty < MinBounty {
+			v.add("bounty", "must be at least %d sats", MinBounty)
+		}
+	case PostTypePoll:
+		validatePollOptions(&v, draft.Options)
+	}
+
+	draft.PostOptions.validate(&v)
+
+	cost := &PostCost{
+		BaseCost:   sub.BaseCost,
+		Multiplier: costMultiplier(recentPosts),
+		Boost:      draft.Boost,
+	}
+	cost.Total = addSaturated(mulSaturated(cost.BaseCost, cost.Multiplier), cost.Boost)
+
+	return cost, v.err()
+}
+
+// costMultiplier returns 10 to the power of recentPosts.
+// The result saturates at the largest power of 10 that fits into an int.
+func costMultiplier(recentPosts int) int {
+	multiplier := 1
+	for i := 0; i < recentPosts && multiplier <= math.MaxInt/10; i++ {
+		multiplier *= 10
+	}
+	return multiplier
+}
+
+// mulSaturated multiplies non-negative ints and returns math.MaxInt on overflow.
+func mulSaturated(a int, b int) int {
+	if a > 0 && b > math.MaxInt/a {
+		return math.MaxInt
+	}
+	return a * b
+}
+
+// addSaturated adds non-negative ints and returns math.MaxInt on overflow.
+func addSaturated(a int, b int) int {
+	if a > math.MaxInt-b {
+		return math.MaxInt
+	}
+	return a + b
+}
+
+func validateUrl(v *ValidationError, rawUrl string) {
+	if rawUrl == "" {
+		v.add("url", "must not be empty")
+		return
+	}
+	u, err := url.ParseRequestURI(rawUrl)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		v.add("url", "must be a valid http or https URL")
+	}
+}
+
+func (c *Client) ValidateDraft(subName string, draft *Draft) (*PostCost, error) {
+	return c.ValidateDraftContext(context.Background(), subName, draft)
+}
+
+// ValidateDraftContext fetches the territory and the recent posts of the authenticated user
+// to validate the draft with ValidateDraft. Errors returned by SN are returned as is
+// while an invalid draft is reported with a *ValidationError.
+// The number of recent posts and therefore the cost is an estimate, see recentPosts.
+func (c *Client) ValidateDraftContext(ctx context.Context, subName string, draft *Draft) (*PostCost, error) {
+	sub, err := c.SubContext(ctx, subName)
+	if err != nil {
+		return nil, err
+	}
+
+	recentPosts, err := c.recentPosts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return ValidateDraft(sub, draft, recentPosts)
+}
+
+// recentPosts returns the number of posts the authenticated user created within the last 10 minutes.
+// It is an estimate since the creation time of posts on SN is compared with the local clock.
+func (c *Client) recentPosts(ctx context.Context) (int, error) {
+	me, err := c.MeContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		n     int
+		query = &ItemsQuery{Sort: "user", Name: me.Name, Type: "posts"}
+	)
+	for {
+		posts, err := c.ItemsContext(ctx, query)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, item := range posts.Items {
+			// posts are sorted from newest to oldest
+			if time.Since(item.CreatedAt) >= 10*time.Minute {
+				return n, nil
+			}
+			n++
+		}
+
+		if posts.Cursor == "" || len(posts.Items) == 0 {
+			return n, nil
+		}
+		query.Cursor = posts.Cursor
+	}
+}
@@ -0,0 +1,159 @@
+package sn
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestValidateDraft(t *testing.T) {
+	sub := &Sub{
+		Name:      "bitcoin",
+		Status:    SubStatusActive,
+		PostTypes: []PostType{PostTypeDiscussion, PostTypeLink},
+		BaseCost:  10,
+	}
+
+	tests := []struct {
+		name   string
+		draft  Draft
+		fields []string
+	}{
+		{
+			name:  "valid discussion",
+			draft: Draft{Type: PostTypeDiscussion, Title: "title"},
+		},
+		{
+			name:  "valid link",
+			draft: Draft{Type: PostTypeLink, Title: "title", Url: "https://stacker.news"},
+		},
+		{
+			name:   "disallowed post type",
+			draft:  Draft{Type: PostTypePoll, Title: "title", Options: []string{"a", "b"}},
+			fields: []string{"type"},
+		},
+		{
+			name:   "empty title",
+			draft:  Draft{Type: PostTypeDiscussion, Title: "  "},
+			fields: []string{"title"},
+		},
+		{
+			name:   "title too long",
+			draft:  Draft{Type: PostTypeDiscussion, Title: strings.Repeat("a", MaxTitleLength+1)},
+			fields: []string{"title"},
+		},
+		{
+			name:  "title with max length in runes",
+			draft: Draft{Type: PostTypeDiscussion, Title: strings.Repeat("ä", MaxTitleLength)},
+		},
+		{
+			name:   "missing url",
+			draft:  Draft{Type: PostTypeLink, Title: "title"},
+			fields: []string{"url"},
+		},
+		{
+			name:   "bad url",
+			draft:  Draft{Type: PostTypeLink, Title: "title", Url: "ftp://stacker.news"},
+			fields: []string{"url"},
+		},
+		{
+			name: "forwards over 100 percent",
+			draft: Draft{Type: PostTypeDiscussion, Title: "title", PostOptions: PostOptions{
+				Forwards: []Forward{{Nym: "a", Pct: 60}, {Nym: "b", Pct: 50}},
+			}},
+			fields: []string{"forwards"},
+		},
+		{
+			name:   "boost too low",
+			draft:  Draft{Type: PostTypeDiscussion, Title: "title", PostOptions: PostOptions{Boost: MinBoost - 1}},
+			fields: []string{"boost"},
+		},
+	}
+	for _, tt := range tests {
+		_, err := ValidateDraft(sub, &tt.draft, 0)
+		if len(tt.fields) == 0 {
+			if err != nil {
+				t.Errorf("%s: expected no error, got %v", tt.name, err)
+			}
+			continue
+		}
+
+		var v *ValidationError
+		if !errors.As(err, &v) {
+			t.Errorf("%s: expected *ValidationError, got %v", tt.name, err)
+			continue
+		}
+		for _, field := range tt.fields {
+			if !hasFieldError(v, field) {
+				t.Errorf("%s: expected error for field %s, got %v", tt.name, field, v)
+			}
+		}
+	}
+}
+
+func hasFieldError(v *ValidationError, field string) bool {
+	for _, e := range v.Errors {
+		if e.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+func TestValidateDraftCost(t *testing.T) {
+	sub := &Sub{Name: "bitcoin", PostTypes: []PostType{PostTypeDiscussion}, BaseCost: 10}
+	draft := &Draft{Type: PostTypeDiscussion, Title: "title", PostOptions: PostOptions{Boost: MinBoost}}
+
+	tests := []struct {
+		recentPosts int
+		multiplier  int
+	}{
+		{-1, 1},
+		{0, 1},
+		{1, 10},
+		{3, 1000},
+	}
+	for _, tt := range tests {
+		cost, err := ValidateDraft(sub, draft, tt.recentPosts)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if cost.Multiplier != tt.multiplier {
+			t.Errorf("%d recent posts: expected multiplier %d, got %d", tt.recentPosts, tt.multiplier, cost.Multiplier)
+		}
+		if total := sub.BaseCost*tt.multiplier + MinBoost; cost.Total != total {
+			t.Errorf("%d recent posts: expected total %d, got %d", tt.recentPosts, total, cost.Total)
+		}
+	}
+}
+
+func TestValidateDraftCostOverflow(t *testing.T) {
+	sub := &Sub{Name: "bitcoin", PostTypes: []PostType{PostTypeDiscussion}, BaseCost: 10}
+	draft := &Draft{Type: PostTypeDiscussion, Title: "title"}
+
+	for _, recentPosts := range []int{19, 21, 100} {
+		cost, err := ValidateDraft(sub, draft, recentPosts)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if cost.Multiplier <= 0 {
+			t.Errorf("%d recent posts: expected positive multiplier, got %d", recentPosts, cost.Multiplier)
+		}
+		if cost.Total != math.MaxInt {
+			t.Errorf("%d recent posts: expected total to saturate at %d, got %d", recentPosts, math.MaxInt, cost.Total)
+		}
+	}
+}
+
+func TestValidateDraftNil(t *testing.T) {
+	var v *ValidationError
+	if _, err := ValidateDraft(nil, &Draft{}, 0); !errors.As(err, &v) {
+		t.Errorf("expected *ValidationError for nil sub, got %v", err)
+	}
+	if _, err := ValidateDraft(&Sub{}, nil, 0); !errors.As(err, &v) {
+		t.Errorf("expected *ValidationError for nil draft, got %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+package sn
+
+import (
+	"context"
+	"time"
+)
+
+// Withdrawl is spelled like in the SN API.
+type Withdrawl struct {
+	Id            int             `json:"id,string"`
+	CreatedAt     time.Time       `json:"createdAt"`
+	Hash          string          `json:"hash"`
+	Bolt11        string          `json:"bolt11"`
+	SatsPaying    int             `json:"satsPaying"`
+	SatsPaid      int             `json:"satsPaid"`
+	SatsFeePaying int             `json:"satsFeePaying"`
+	SatsFeePaid   int             `json:"satsFeePaid"`
+	Status        WithdrawlStatus `json:"status"`
+	AutoWithdraw  bool            `json:"autoWithdraw"`
+	Preimage      string          `json:"preimage"`
+}
+
+// WithdrawlStatus is empty while the payment is pending.
+type WithdrawlStatus string
+
+const (
+	WithdrawlStatusConfirmed           WithdrawlStatus = "CONFIRMED"
+	WithdrawlStatusInsufficientBalance WithdrawlStatus = "INSUFFICIENT_BALANCE"
+	WithdrawlStatusInvalidPayment      WithdrawlStatus = "INVALID_PAYMENT"
+	WithdrawlStatusPathfindingTimeout  WithdrawlStatus = "PATHFINDING_TIMEOUT"
+	WithdrawlStatusRouteNotFound       WithdrawlStatus = "ROUTE_NOT_FOUND"
+	WithdrawlStatusUnknownFailure      WithdrawlStatus = "UNKNOWN_FAILURE"
+)
+
+func (s WithdrawlStatus) Pending() bool {
+	return s == ""
+}
+
+func (s WithdrawlStatus) Confirmed() bool {
+	return s == WithdrawlStatusConfirmed
+}
+
+func (s WithdrawlStatus) Failed() bool {
+	return !s.Pending() && !s.Confirmed()
+}
+
+const withdrawlFields = `
+fragment WithdrawlFields on Withdrawl {
+	id
+	createdAt
+	hash
+	bolt11
+	satsPaying
+	satsPaid
+	satsFeePaying
+	satsFeePaid
+	status
+	autoWithdraw
+	preimage
+}`
+
+func (c *Client) Withdraw(bolt11 string, maxFee int) (*Withdrawl, error) {
+	return c.WithdrawContext(context.Background(), bolt11, maxFee)
+}
+
+// WithdrawContext pays the bolt11 from the SN wallet.
+// The returned withdrawal is usually still pending.
+func (c *Client) WithdrawContext(ctx context.Context, bolt11 string, maxFee int) (*Withdrawl, error) {
+	query := withdrawlFields + `
+	mutation createWithdrawl($invoice: String!, $maxFee: Int!) {
+		createWithdrawl(invoice: $invoice, maxFee: $maxFee) {
+			...WithdrawlFields
+		}
+	}`
+	variables := map[string]interface{}{
+		"invoice": bolt11,
+		"maxFee":  maxFee,
+	}
+
+	data, err := Do[struct {
+		CreateWithdrawl Withdrawl `json:"createWithdrawl"`
+	}](ctx, c, query, variables)
+	if err != nil {
+		return nil, err
+	}
+	return &data.CreateWithdrawl, nil
+}
+
+func (c *Client) SendToLnAddr(addr string, amount int, maxFee int, comment string) (*Withdrawl, error) {
+	return c.SendToLnAddrContext(context.Background(), addr, amount, maxFee, comment)
+}
+
+// SendToLnAddrContext pays amount sats to the lightning address from the SN wallet.
+// The returned withdrawal is usually still pending.
+func (c *Client) SendToLnAddrContext(ctx context.Context, addr string, amount int, maxFee int, comment string) (*Withdrawl, error) {
+	query := withdrawlFields + `
+	mutation sendToLnAddr($addr: String!, $amount: Int!, $maxFee: Int!, $comment: String) {
+		sendToLnAddr(addr: $addr, amount: $amount, maxFee: $maxFee, comment: $comment) {
+			...WithdrawlFields
+		}
+	}`
+	variables := map[string]interface{}{
+		"addr":   addr,
+		"amount": amount,
+		"maxFee": maxFee,
+	}
+	if comment != "" {
+		variables["comment"] = comment
+	}
+
+	data, err := Do[struct {
+		SendToLnAddr Withdrawl `json:"sendToLnAddr"`
+	}](ctx, c, query, variables)
+	if err != nil {
+		return nil, err
+	}
+	return &data.SendToLnAddr, nil
+}
+
+func (c *Client) Withdrawl(id int) (*Withdrawl, error) {
+	return c.WithdrawlContext(context.Background(), id)
+}
+
+func (c *Client) WithdrawlContext(ctx context.Context, id int) (*Withdrawl, error) {
+	query := withdrawlFields + `
+	query withdrawl($id: ID!) {
+		withdrawl(id: $id) {
+			...WithdrawlFields
+		}
+	}`
+	variables := map[string]interface{}{
+		"id": id,
+	}
+
+	data, err := Do[struct {
+		Withdrawl Withdrawl `json:"withdrawl"`
+	}](ctx, c, query, variables)
+	if err != nil {
+		return nil, err
+	}
+	return &data.Withdrawl, nil
+}
+
+func (c *Client) WaitForWithdrawl(id int, opts *PollOptions) (*Withdrawl, error) {
+	return c.WaitForWithdrawlContext(context.Background(), id, opts)
+}
+
+// WaitForWithdrawlContext polls the withdrawal until it is no longer pending.
+// Failed withdrawals are not returned as errors; check Status instead.
+func (c *Client) WaitForWithdrawlContext(ctx context.Context, id int, opts *PollOptions) (*Withdrawl, error) {
+	if opts == nil {
+		opts = &DefaultPollOptions
+	}
+
+	var interval time.Duration
+	for {
+		w, err := c.WithdrawlContext(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if !w.Status.Pending() {
+			return w, nil
+		}
+
+		interval = opts.next(interval)
+		if err = sleep(ctx, interval); err != nil {
+			return nil, err
+		}
+	}
+}